@@ -28,7 +28,11 @@ var app = &cli.App{
 				},
 				&cli.StringFlag{
 					Name:  "range",
-					Usage: "Sheet range to update or get (A1 notation)",
+					Usage: "Sheet range to update or get (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "meta",
+					Usage: "key=value developer metadata selector to find the sheet or rows by (--range is then relative to that sheet, and cannot be given with metadata on rows or columns)",
 				},
 				&cli.BoolFlag{
 					Name:  "append",
@@ -39,6 +43,10 @@ var app = &cli.App{
 					Value: ",",
 					Usage: `Record separator (use '\t' for tab)`,
 				},
+				&cli.BoolFlag{
+					Name:  "merges",
+					Usage: "When reading, also list any merged cells in range to stderr",
+				},
 				&cli.BoolFlag{
 					Name:     "read",
 					Usage:    "Force gsheet to read from range instead of write to range. This is useful if stdin is set to a non-character device such as when running a script from cron.",
@@ -64,6 +72,23 @@ var app = &cli.App{
 				},
 			},
 		},
+		{
+			Name:     "sheets",
+			Usage:    "List the sheets of a spreadsheet with their ids, sizes and properties",
+			Action:   listSheetsAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.BoolFlag{
+					Name:  "json",
+					Usage: "Output as json",
+				},
+			},
+		},
 		{
 			Name:      "sheetInfo",
 			Usage:     "Dump info about the spreadsheet as json",
@@ -84,7 +109,7 @@ var app = &cli.App{
 				},
 				&cli.StringSliceFlag{
 					Name:  "range",
-					Usage: "Sheet range to update or get (A1 notation)",
+					Usage: "Sheet range to update or get (A1 notation or named range)",
 				},
 			},
 		},
@@ -121,6 +146,10 @@ var app = &cli.App{
 					Name:  "name",
 					Usage: "name of the sheet to delete",
 				},
+				&cli.StringFlag{
+					Name:  "meta",
+					Usage: "key=value developer metadata selector to find the sheet by (instead of --name)",
+				},
 			},
 		},
 		{
@@ -138,6 +167,10 @@ var app = &cli.App{
 					Name:  "name",
 					Usage: "name of the sheet to sort",
 				},
+				&cli.StringFlag{
+					Name:  "meta",
+					Usage: "key=value developer metadata selector to find the sheet by (instead of --name)",
+				},
 				&cli.BoolFlag{
 					Name:    "ascending",
 					Aliases: []string{"asc"},
@@ -153,41 +186,942 @@ var app = &cli.App{
 			},
 		},
 
+		{
+			Name:     "format",
+			Usage:    "Apply number formats, fonts, colors, alignment and borders to a range",
+			Action:   formatAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "Sheet range to format (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "number-format",
+					Usage: "Number or date pattern (eg '$#,##0.00', '0.0%', 'yyyy-mm-dd')",
+				},
+				&cli.StringFlag{
+					Name:  "number-type",
+					Usage: "One of TEXT, NUMBER, PERCENT, CURRENCY, DATE, TIME, DATE_TIME, SCIENTIFIC (default DATE, TIME or DATE_TIME for date and time patterns, otherwise NUMBER)",
+				},
+				&cli.BoolFlag{
+					Name:  "bold",
+					Usage: "Set bold text (use --bold=false to unset)",
+				},
+				&cli.BoolFlag{
+					Name:  "italic",
+					Usage: "Set italic text (use --italic=false to unset)",
+				},
+				&cli.StringFlag{
+					Name:  "fg",
+					Usage: "Text color as hex (eg '#ff0000')",
+				},
+				&cli.StringFlag{
+					Name:  "bg",
+					Usage: "Background color as hex (eg '#ffff00')",
+				},
+				&cli.StringFlag{
+					Name:  "halign",
+					Usage: "Horizontal alignment: LEFT, CENTER or RIGHT",
+				},
+				&cli.StringFlag{
+					Name:  "valign",
+					Usage: "Vertical alignment: TOP, MIDDLE or BOTTOM",
+				},
+				&cli.StringFlag{
+					Name:  "wrap",
+					Usage: "Wrap strategy: OVERFLOW_CELL, CLIP or WRAP",
+				},
+				&cli.StringFlag{
+					Name:  "border",
+					Usage: "Border style for every cell edge: DOTTED, DASHED, SOLID, SOLID_MEDIUM, SOLID_THICK, DOUBLE or NONE",
+				},
+				&cli.StringFlag{
+					Name:  "border-color",
+					Usage: "Border color as hex (eg '#000000')",
+				},
+			},
+		},
+		{
+			Name:     "validate",
+			Usage:    "Set or clear data validation (dropdowns, checkboxes, conditions) on a range",
+			Action:   validateAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "Sheet range to validate (A1 notation or named range)",
+				},
+				&cli.StringSliceFlag{
+					Name:  "one-of",
+					Usage: "Only allow these comma separated values (shown as a dropdown)",
+				},
+				&cli.StringFlag{
+					Name:  "one-of-range",
+					Usage: "Only allow values found in this range (A1 notation)",
+				},
+				&cli.BoolFlag{
+					Name:  "checkbox",
+					Usage: "Show cells as checkboxes",
+				},
+				&cli.StringFlag{
+					Name:  "formula",
+					Usage: "Only allow values for which this custom formula is true",
+				},
+				&cli.StringFlag{
+					Name:  "condition",
+					Usage: "Any other condition type (eg NUMBER_BETWEEN, NUMBER_GREATER, DATE_AFTER, TEXT_IS_EMAIL); see --value",
+				},
+				&cli.StringSliceFlag{
+					Name:  "value",
+					Usage: "Value(s) for --condition",
+				},
+				&cli.BoolFlag{
+					Name:  "strict",
+					Usage: "Reject invalid input (use --strict=false to only show a warning)",
+					Value: true,
+				},
+				&cli.StringFlag{
+					Name:  "message",
+					Usage: "Input message to show when a validated cell is selected",
+				},
+				&cli.BoolFlag{
+					Name:  "clear",
+					Usage: "Remove all data validation from the range",
+				},
+			},
+		},
+		{
+			Name:     "namedRange",
+			Usage:    "List, add, update and delete named ranges",
+			Category: "Sheets",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List named ranges with their sheet and A1 bounds",
+					Action: namedRangeListAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output as json",
+						},
+					},
+				},
+				{
+					Name:   "add",
+					Usage:  "Create a new named range",
+					Action: namedRangeAddAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "name",
+							Usage: "name of the new range",
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "Sheet range to name (A1 notation)",
+						},
+					},
+				},
+				{
+					Name:   "update",
+					Usage:  "Rename a named range or change the cells it covers",
+					Action: namedRangeUpdateAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "name",
+							Usage: "name of the range to update",
+						},
+						&cli.StringFlag{
+							Name:  "new-name",
+							Usage: "new name to give the range",
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "new cells for the range to cover (A1 notation)",
+						},
+					},
+				},
+				{
+					Name:   "delete",
+					Usage:  "Delete a named range (the cells are not changed)",
+					Action: namedRangeDeleteAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "name",
+							Usage: "name of the range to delete",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "protection",
+			Usage:    "List, add, update and remove protected ranges and sheets",
+			Category: "Sheets",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List protected ranges and sheets with their editors",
+					Action: protectionListAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output as json",
+						},
+					},
+				},
+				{
+					Name:   "add",
+					Usage:  "Protect a range (or a whole sheet if --range is a sheet title)",
+					Action: protectionAddAction,
+					Flags: append([]cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "Sheet range or sheet title to protect",
+						},
+					}, protectionFlags()...),
+				},
+				{
+					Name:   "update",
+					Usage:  "Change the given options of an existing protection",
+					Action: protectionUpdateAction,
+					Flags: append([]cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.Int64Flag{
+							Name:  "protection-id",
+							Usage: "id of the protection (see 'protection list')",
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "Sheet range or sheet title to move the protection to",
+						},
+					}, protectionFlags()...),
+				},
+				{
+					Name:   "remove",
+					Usage:  "Remove a protection",
+					Action: protectionRemoveAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.Int64Flag{
+							Name:  "protection-id",
+							Usage: "id of the protection (see 'protection list')",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "freeze",
+			Usage:    "Freeze header rows and columns of a sheet",
+			Action:   freezeAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "name of the sheet",
+				},
+				&cli.Int64Flag{
+					Name:  "rows",
+					Usage: "number of rows to freeze (0 to unfreeze)",
+					Value: -1,
+				},
+				&cli.Int64Flag{
+					Name:  "cols",
+					Usage: "number of columns to freeze (0 to unfreeze)",
+					Value: -1,
+				},
+			},
+		},
+		{
+			Name:     "resize",
+			Usage:    "Set row heights or column widths, or fit them to their contents",
+			Action:   resizeAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "whole rows (eg 'Sheet1!2:5') or columns (eg 'Sheet1!A:C') to resize",
+				},
+				&cli.Int64Flag{
+					Name:  "size",
+					Usage: "height or width in pixels",
+				},
+				&cli.BoolFlag{
+					Name:  "auto",
+					Usage: "Fit the rows or columns to their contents",
+				},
+			},
+		},
+		{
+			Name:     "hide",
+			Usage:    "Hide or unhide rows or columns",
+			Action:   hideAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "whole rows (eg 'Sheet1!2:5') or columns (eg 'Sheet1!A:C') to hide",
+				},
+				&cli.BoolFlag{
+					Name:  "unhide",
+					Usage: "Show the rows or columns again",
+				},
+			},
+		},
+		{
+			Name:     "group",
+			Usage:    "Group, ungroup, collapse or expand rows or columns",
+			Action:   groupAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "whole rows (eg 'Sheet1!2:5') or columns (eg 'Sheet1!A:C') to group",
+				},
+				&cli.BoolFlag{
+					Name:  "ungroup",
+					Usage: "Remove one level of grouping instead of adding one",
+				},
+				&cli.BoolFlag{
+					Name:  "collapse",
+					Usage: "Collapse an existing group (use --collapse=false to expand it)",
+				},
+			},
+		},
+		{
+			Name:     "merge",
+			Usage:    "Merge, unmerge or list merged cells in a range",
+			Action:   mergeAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "Sheet range to merge (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "type",
+					Usage: "all (merge into one cell), columns (merge each column) or rows (merge each row)",
+					Value: "all",
+				},
+				&cli.BoolFlag{
+					Name:  "unmerge",
+					Usage: "Unmerge all merged cells in the range",
+				},
+				&cli.BoolFlag{
+					Name:  "list",
+					Usage: "List the merged cells which overlap the range",
+				},
+			},
+		},
+		{
+			Name:     "chart",
+			Usage:    "List, add, update and delete charts",
+			Category: "Sheets",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List charts with their type, source range and position",
+					Action: chartListAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output as json",
+						},
+					},
+				},
+				{
+					Name:   "add",
+					Usage:  "Create a chart from a spec file",
+					Action: chartAddAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "spec",
+							Usage: "path to a yaml or json chart spec file ('-' for stdin)",
+						},
+					},
+				},
+				{
+					Name:   "update",
+					Usage:  "Replace a chart's spec from a spec file",
+					Action: chartUpdateAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.Int64Flag{
+							Name:  "chart-id",
+							Usage: "id of the chart (see 'chart list')",
+						},
+						&cli.StringFlag{
+							Name:  "spec",
+							Usage: "path to a yaml or json chart spec file ('-' for stdin)",
+						},
+					},
+				},
+				{
+					Name:   "delete",
+					Usage:  "Delete a chart",
+					Action: chartDeleteAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.Int64Flag{
+							Name:  "chart-id",
+							Usage: "id of the chart (see 'chart list')",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "pivot",
+			Usage:    "Create (or recreate) a pivot table from a spec file",
+			Action:   pivotAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "spec",
+					Usage: "path to a yaml or json pivot spec file ('-' for stdin)",
+				},
+			},
+		},
+		{
+			Name:     "metadata",
+			Usage:    "Set, search and delete developer metadata on spreadsheets, sheets, rows and columns",
+			Category: "Sheets",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "set",
+					Usage:  "Attach a key/value pair to the spreadsheet, a sheet, or rows or columns",
+					Action: metadataSetAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "sheet title, or whole rows ('Sheet1!3:3') or columns ('Sheet1!C:C'); omit for the spreadsheet itself",
+						},
+						&cli.StringFlag{
+							Name:  "key",
+							Usage: "metadata key",
+						},
+						&cli.StringFlag{
+							Name:  "value",
+							Usage: "metadata value",
+						},
+					},
+				},
+				{
+					Name:   "search",
+					Usage:  "List metadata with a key (and value) and where it is attached",
+					Action: metadataSearchAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "key",
+							Usage: "metadata key",
+						},
+						&cli.StringFlag{
+							Name:  "value",
+							Usage: "metadata value (omit to match any value)",
+						},
+						&cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output as json",
+						},
+					},
+				},
+				{
+					Name:   "delete",
+					Usage:  "Delete metadata with a key (and value)",
+					Action: metadataDeleteAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "key",
+							Usage: "metadata key",
+						},
+						&cli.StringFlag{
+							Name:  "value",
+							Usage: "metadata value (omit to match any value)",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "note",
+			Usage:    "Read or set cell notes",
+			Action:   noteAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "Sheet range to read or set notes on (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "set",
+					Usage: "Set the note of every cell in range (use --set '' to remove notes)",
+				},
+				&cli.StringFlag{
+					Name:  "csv",
+					Usage: "Set notes from a csv file of 'cell,note' records ('-' for stdin)",
+				},
+			},
+		},
+		{
+			Name:     "link",
+			Usage:    "Set cells to display hyperlinks",
+			Action:   linkAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "range",
+					Usage: "Sheet range to set links in (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "url",
+					Usage: "URL to link to",
+				},
+				&cli.StringFlag{
+					Name:  "text",
+					Usage: "Text to display (defaults to the url)",
+				},
+				&cli.StringFlag{
+					Name:  "csv",
+					Usage: "Set links from a csv file of 'cell,url[,text]' records ('-' for stdin)",
+				},
+			},
+		},
+		{
+			Name:     "copy-range",
+			Usage:    "Copy cells to another range, or fill formulas down with --fill",
+			Action:   copyRangeAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "from",
+					Usage: "Range to copy (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "Range (or top-left cell) to paste into",
+				},
+				&cli.StringFlag{
+					Name:  "paste",
+					Usage: "What to paste: normal, values, formats, formulas, no-borders, validation or conditional",
+					Value: "normal",
+				},
+				&cli.BoolFlag{
+					Name:  "transpose",
+					Usage: "Paste rows as columns and columns as rows",
+				},
+				&cli.Int64Flag{
+					Name:  "fill",
+					Usage: "Instead of pasting, auto-fill this many rows below the range (negative to fill above)",
+				},
+			},
+		},
+		{
+			Name:     "move-range",
+			Usage:    "Move cells to another place, updating formulas which refer to them",
+			Action:   moveRangeAction,
+			Category: "Sheets",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "id",
+					Usage:   "id of the spreadsheet document",
+					EnvVars: []string{"GSHEET_ID"},
+				},
+				&cli.StringFlag{
+					Name:  "from",
+					Usage: "Range to move (A1 notation or named range)",
+				},
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "Top-left cell of the destination",
+				},
+				&cli.StringFlag{
+					Name:  "paste",
+					Usage: "What to paste: normal, values, formats, formulas, no-borders, validation or conditional",
+					Value: "normal",
+				},
+			},
+		},
+		{
+			Name:     "clean",
+			Usage:    "Remove duplicate rows, trim whitespace or split text into columns",
+			Category: "Sheets",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "dedupe",
+					Usage:  "Delete rows which duplicate an earlier row in the range",
+					Action: cleanDedupeAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "Sheet range to dedupe (A1 notation or named range)",
+						},
+						&cli.StringSliceFlag{
+							Name:  "columns",
+							Usage: "Only compare these columns (eg 'A,C:D'); default is whole rows",
+						},
+					},
+				},
+				{
+					Name:   "trim",
+					Usage:  "Trim leading, trailing and repeated whitespace from every cell in the range",
+					Action: cleanTrimAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "Sheet range to trim (A1 notation or named range)",
+						},
+					},
+				},
+				{
+					Name:   "split",
+					Usage:  "Split the text in a column into the columns to its right",
+					Action: cleanSplitAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "range",
+							Usage: "Single column range to split (eg 'Sheet1!B2:B')",
+						},
+						&cli.StringFlag{
+							Name:  "delimiter",
+							Usage: "comma, semicolon, period, space, autodetect or any other string to split on",
+							Value: "autodetect",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "schema",
+			Usage:    "Export a spreadsheet's structure or make another match it",
+			Category: "Sheets",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "export",
+					Usage:  "Write the sheets, headers, formats, validations, protections, named ranges and conditional formats as yaml",
+					Action: schemaExportAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+					},
+				},
+				{
+					Name:   "apply",
+					Usage:  "Show (and with --yes make) the changes needed to match a schema file",
+					Action: schemaApplyAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "id",
+							Usage:   "id of the spreadsheet document",
+							EnvVars: []string{"GSHEET_ID"},
+						},
+						&cli.StringFlag{
+							Name:  "spec",
+							Usage: "path to a yaml schema file ('-' for stdin)",
+						},
+						&cli.BoolFlag{
+							Name:  "yes",
+							Usage: "Make the changes, rather than only showing them",
+						},
+					},
+				},
+			},
+		},
+
 		// Files
 		{
 			Name:      "createFolder",
 			Usage:     "Creates a new folder",
-			ArgsUsage: "NAME",
+			ArgsUsage: "NAME (or PATH with -p)",
 			Action:    createFolderAction,
 			Category:  "Files",
 			Flags: []cli.Flag{
 				&cli.StringFlag{
 					Name:    "parent",
-					Usage:   "The id of a parent folder to act on.",
+					Usage:   "The id (or path) of a parent folder to act on.",
 					Value:   "root",
 					EnvVars: []string{"GSHEET_PARENT"},
 				},
+				&cli.BoolFlag{
+					Name:    "parents",
+					Aliases: []string{"p"},
+					Usage:   "NAME is a path (eg 'Reports/2024/Q1'); create any missing folders on it",
+				},
 			},
 		},
 		{
 			Name:      "delete",
-			Usage:     "Delete file(s) from drive (careful, does not trash them!)",
+			Usage:     "Move file(s) to the trash (or delete them permanently with --permanent)",
 			ArgsUsage: "FILE_ID [FILE_ID...]",
 			Action:    deleteAction,
 			Category:  "Files",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "permanent",
+					Usage: "Delete immediately instead of moving to the trash (cannot be undone)",
+				},
+				&cli.BoolFlag{
+					Name:    "yes",
+					Aliases: []string{"y"},
+					Usage:   "Do not ask for confirmation before deleting permanently",
+				},
+			},
+		},
+		{
+			Name:     "trash",
+			Usage:    "List, restore or empty trashed files",
+			Category: "Files",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List file names and ids in the trash",
+					Action: trashListAction,
+				},
+				{
+					Name:      "restore",
+					Usage:     "Restore file(s) from the trash",
+					ArgsUsage: "FILE_ID [FILE_ID...]",
+					Action:    trashRestoreAction,
+				},
+				{
+					Name:   "empty",
+					Usage:  "Permanently delete all files in the trash",
+					Action: trashEmptyAction,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:    "yes",
+							Aliases: []string{"y"},
+							Usage:   "Do not ask for confirmation",
+						},
+					},
+				},
+			},
 		},
 		{
 			Name:     "list",
 			Usage:    "List file names and ids",
 			Action:   listAction,
 			Category: "Files",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.StringFlag{
 					Name:    "parent",
 					Usage:   "id of the folder to list (use 'root' for drive root)",
 					EnvVars: []string{"GSHEET_PARENT"},
 				},
-			},
+				&cli.BoolFlag{
+					Name:    "recursive",
+					Aliases: []string{"R"},
+					Usage:   "List the contents of sub-folders too, with their paths (from 'root' if no --parent)",
+				},
+			}, listingFlags()...),
+		},
+		{
+			Name:     "search",
+			Usage:    "Find files by name, type, folder, date, owner and more",
+			Action:   searchAction,
+			Category: "Files",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "name",
+					Usage: "file name (exact match)",
+				},
+				&cli.StringFlag{
+					Name:  "name-contains",
+					Usage: "words the file name contains (matches from the start of words)",
+				},
+				&cli.StringFlag{
+					Name:  "text",
+					Usage: "words in the file's name, description or content",
+				},
+				&cli.StringFlag{
+					Name:  "type",
+					Usage: "kind of Google file (spreadsheet, document, presentation, folder, ...) or a MIME type",
+				},
+				&cli.StringFlag{
+					Name:  "parent",
+					Usage: "id of the folder the files are in (not searched recursively)",
+				},
+				&cli.StringFlag{
+					Name:  "modified-after",
+					Usage: "date (YYYY-MM-DD) or RFC 3339 time",
+				},
+				&cli.StringFlag{
+					Name:  "modified-before",
+					Usage: "date (YYYY-MM-DD) or RFC 3339 time",
+				},
+				&cli.StringFlag{
+					Name:  "owner",
+					Usage: "email address of the owner",
+				},
+				&cli.BoolFlag{
+					Name:  "shared-with-me",
+					Usage: "Only files in 'Shared with me'",
+				},
+				&cli.BoolFlag{
+					Name:  "trashed",
+					Usage: "Search the trash instead",
+				},
+				&cli.BoolFlag{
+					Name:  "starred",
+					Usage: "Only starred files (--starred=false for only unstarred ones)",
+				},
+				&cli.StringSliceFlag{
+					Name:  "property",
+					Usage: "app property KEY=VALUE the file must have (may be repeated)",
+				},
+				&cli.StringFlag{
+					Name:  "query",
+					Usage: "extra condition in the Drive query language, eg \"viewedByMeTime > '2024-01-01'\"",
+				},
+				&cli.BoolFlag{
+					Name:  "show-query",
+					Usage: "Print the compiled Drive query to stderr",
+				},
+			}, listingFlags()...),
 		},
 		{
 			Name:      "upload",
@@ -207,6 +1141,29 @@ var app = &cli.App{
 					Usage:       "Name to give the uploaded file",
 					DefaultText: "Name of input file",
 				},
+				&cli.BoolFlag{
+					Name:  "convert",
+					Usage: "Also convert types which are not converted by default (eg .txt, .html, .xls and .pptx) to Google files",
+				},
+				&cli.BoolFlag{
+					Name:  "no-convert",
+					Usage: "Upload the file as it is (without converting .csv, .tsv, .xlsx, .ods, .docx or .md files)",
+				},
+				&cli.Float64Flag{
+					Name:  "chunk-size",
+					Usage: "Upload in chunks of this many MiB (rounded up to a multiple of 0.25), retrying failed chunks",
+					Value: 8,
+				},
+				&cli.IntFlag{
+					Name:  "retries",
+					Usage: "How many times in a row to retry a failed chunk before giving up (0 disables retries)",
+					Value: 5,
+				},
+				&cli.BoolFlag{
+					Name:    "quiet",
+					Aliases: []string{"q"},
+					Usage:   "Do not show upload progress on stderr",
+				},
 			},
 		},
 		{
@@ -215,6 +1172,161 @@ var app = &cli.App{
 			Action:    downloadAction,
 			ArgsUsage: "FILE_ID",
 			Category:  "Files",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "output",
+					Aliases: []string{"o"},
+					Usage:   "Write to FILE instead of stdout, resuming an interrupted download to the same FILE",
+				},
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Usage:   "export Google files as this format: csv, tsv, xlsx, ods, pdf or zip (html) for Sheets; txt, docx, odt, md, html, pdf, rtf or epub for Docs; txt, pptx, odp or pdf for Slides; or a MIME type",
+				},
+				&cli.StringFlag{
+					Name:  "sheet",
+					Usage: "title or id of the sheet to export from a Sheets doc (csv, tsv or pdf)",
+				},
+				&cli.BoolFlag{
+					Name:  "landscape",
+					Usage: "Print Sheets pdf exports in landscape",
+				},
+				&cli.BoolFlag{
+					Name:  "fit-width",
+					Usage: "Scale Sheets pdf exports to the page width",
+				},
+				&cli.BoolFlag{
+					Name:  "gridlines",
+					Usage: "Print gridlines in Sheets pdf exports",
+				},
+				&cli.StringFlag{
+					Name:  "paper-size",
+					Usage: "paper size of Sheets pdf exports, eg letter, legal or A4",
+				},
+			},
+		},
+		{
+			Name:      "mv",
+			Usage:     "Move file(s) into a folder",
+			ArgsUsage: "FILE_ID [FILE_ID...] FOLDER_ID",
+			Action:    mvAction,
+			Category:  "Files",
+		},
+		{
+			Name:      "cp",
+			Usage:     "Copy a file",
+			ArgsUsage: "FILE_ID",
+			Action:    cpAction,
+			Category:  "Files",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:        "name",
+					Usage:       "Name to give the copy",
+					DefaultText: "Name of the original",
+				},
+				&cli.StringFlag{
+					Name:        "parent",
+					Usage:       "id of the folder to copy into",
+					DefaultText: "Folder of the original",
+				},
+			},
+		},
+		{
+			Name:      "rename",
+			Usage:     "Rename a file or change its description",
+			ArgsUsage: "FILE_ID [NAME]",
+			Action:    renameAction,
+			Category:  "Files",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "description",
+					Usage: "Set the file's description",
+				},
+			},
+		},
+		{
+			Name:     "share",
+			Usage:    "Share files with users, groups, domains or anyone and manage their permissions",
+			Category: "Files",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "add",
+					Usage:     "Grant a role on a file to users, groups, a domain or anyone",
+					ArgsUsage: "FILE_ID",
+					Action:    shareAddAction,
+					Flags: []cli.Flag{
+						&cli.StringSliceFlag{
+							Name:  "user",
+							Usage: "email address of a user to share with (may be repeated)",
+						},
+						&cli.StringSliceFlag{
+							Name:  "group",
+							Usage: "email address of a group to share with (may be repeated)",
+						},
+						&cli.StringFlag{
+							Name:  "domain",
+							Usage: "share with everybody in this domain",
+						},
+						&cli.BoolFlag{
+							Name:  "anyone",
+							Usage: "share with anybody who has the link",
+						},
+						&cli.StringFlag{
+							Name:  "role",
+							Usage: "reader, commenter, writer, fileOrganizer or organizer",
+							Value: "reader",
+						},
+						&cli.BoolFlag{
+							Name:  "discoverable",
+							Usage: "let --domain or --anyone find the file by searching",
+						},
+						&cli.BoolFlag{
+							Name:  "no-notify",
+							Usage: "do not send users and groups a notification email",
+						},
+						&cli.StringFlag{
+							Name:  "message",
+							Usage: "message to include in the notification email",
+						},
+					},
+				},
+				{
+					Name:      "list",
+					Usage:     "List the permissions on a file",
+					ArgsUsage: "FILE_ID",
+					Action:    shareListAction,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "json",
+							Usage: "Output as json",
+						},
+					},
+				},
+				{
+					Name:      "update",
+					Usage:     "Change the role of a permission",
+					ArgsUsage: "FILE_ID WHO (permission id, email, domain or 'anyone')",
+					Action:    shareUpdateAction,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "role",
+							Usage: "reader, commenter, writer, fileOrganizer or organizer",
+						},
+					},
+				},
+				{
+					Name:      "revoke",
+					Usage:     "Remove permissions from a file",
+					ArgsUsage: "FILE_ID WHO [WHO...] (permission id, email, domain or 'anyone')",
+					Action:    shareRevokeAction,
+				},
+				{
+					Name:      "transfer",
+					Usage:     "Make another user the owner of a file",
+					ArgsUsage: "FILE_ID EMAIL",
+					Action:    shareTransferAction,
+				},
+			},
 		},
 		{
 			Name:      "info",
@@ -225,3 +1337,60 @@ var app = &cli.App{
 		},
 	},
 }
+
+// Flags shared by the 'protection add' and 'protection update' commands
+func protectionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "description of the protection",
+		},
+		&cli.BoolFlag{
+			Name:  "warning-only",
+			Usage: "Allow anybody to edit after confirming a warning",
+		},
+		&cli.StringSliceFlag{
+			Name:  "user",
+			Usage: "email address of a user allowed to edit (may be repeated)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "group",
+			Usage: "email address of a group allowed to edit (may be repeated)",
+		},
+		&cli.BoolFlag{
+			Name:  "domain",
+			Usage: "Allow users in the document's domain to edit",
+		},
+		&cli.StringSliceFlag{
+			Name:  "unprotected",
+			Usage: "range within a protected sheet which remains editable (may be repeated)",
+		},
+	}
+}
+
+// Flags shared by the 'list' and 'search' commands (see printFiles)
+func listingFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "long",
+			Aliases: []string{"l"},
+			Usage:   "Also show the type, size, modified time, owner and whether the file is shared",
+		},
+		&cli.StringFlag{
+			Name:  "order-by",
+			Usage: "Sort keys, eg 'folder,name' or 'modifiedTime desc'",
+		},
+		&cli.IntFlag{
+			Name:  "max",
+			Usage: "List at most this many files",
+		},
+		&cli.StringFlag{
+			Name:  "fields",
+			Usage: "Comma separated file fields to fetch, eg 'id,name,webViewLink' (implies --json)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Output as json",
+		},
+	}
+}
@@ -1,36 +1,124 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cristoper/gsheet/gdrive"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/api/drive/v3"
 )
 
 func deleteAction(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New("Missing FILE_ID")
+	}
+	if c.Bool("permanent") && !c.Bool("yes") {
+		if err := confirm(c, fmt.Sprintf("Permanently delete %d file(s)? This cannot be undone.", c.NArg())); err != nil {
+			return err
+		}
+	}
+	ids, err := fileArgs(c)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if c.Bool("permanent") {
+			if err := driveSvc.DeleteFile(id); err != nil {
+				return err
+			}
+			fmt.Fprintf(c.App.ErrWriter, "Deleted file %s\n", id)
+			continue
+		}
+		if _, err := driveSvc.TrashFile(id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Moved file %s to the trash\n", id)
+	}
+	return nil
+}
+
+// confirm asks the user a yes/no 'question' on stderr and reads the answer
+// from stdin. Anything but y or yes is no, which returns an error (as does
+// stdin not being a terminal, since there is nobody to ask; pass --yes).
+func confirm(c *cli.Context, question string) error {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return errors.New("Cannot ask for confirmation: stdin is not a terminal (use --yes)")
+	}
+	fmt.Fprintf(c.App.ErrWriter, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		return errors.New("Cancelled")
+	}
+	return nil
+}
+
+func trashListAction(c *cli.Context) error {
+	files, err := driveSvc.Trashed()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		fmt.Fprintf(c.App.Writer, "%-16s\t%1s\n", f.Name, f.Id)
+	}
+	return nil
+}
+
+func trashRestoreAction(c *cli.Context) error {
 	if c.NArg() < 1 {
 		return errors.New("Missing FILE_ID")
 	}
 	for _, id := range c.Args().Slice() {
-		err := driveSvc.DeleteFile(id)
+		file, err := driveSvc.UntrashFile(id)
 		if err != nil {
 			return err
 		}
-		fmt.Fprintf(c.App.ErrWriter, "Deleted file %s\n", id)
+		fmt.Fprintf(c.App.ErrWriter, "Restored file %s (%s)\n", file.Name, file.Id)
 	}
 	return nil
 }
 
+func trashEmptyAction(c *cli.Context) error {
+	if !c.Bool("yes") {
+		if err := confirm(c, "Permanently delete all files in the trash? This cannot be undone."); err != nil {
+			return err
+		}
+	}
+	if err := driveSvc.EmptyTrash(); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.App.ErrWriter, "Emptied the trash")
+	return nil
+}
+
 func createFolderAction(c *cli.Context) error {
 	if c.NArg() < 1 {
 		return errors.New("NAME is required")
 	}
 	name := c.Args().Get(0)
-	dir, err := driveSvc.CreateFolder(name, c.String("parent"))
+	var dir *drive.File
+	var err error
+	if c.Bool("parents") {
+		dir, err = driveSvc.CreateFolders(name, c.String("parent"))
+	} else {
+		dir, err = driveSvc.CreateFolder(name, c.String("parent"))
+	}
 	if err == nil {
 		fmt.Fprintf(c.App.ErrWriter, "Created directory named %s with id %s\n", dir.Name, dir.Id)
 	}
@@ -41,20 +129,67 @@ func downloadAction(c *cli.Context) error {
 	if c.NArg() < 1 {
 		return errors.New("FILE_ID is required")
 	}
-	id := c.Args().Get(0)
-	contents, err := driveSvc.FileContents(id)
+	id, err := resolveId(c.Args().First())
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(c.App.Writer, string(contents))
-	return nil
+	opts := &gdrive.ExportOptions{
+		Format:    c.String("format"),
+		Landscape: c.Bool("landscape"),
+		FitWidth:  c.Bool("fit-width"),
+		Gridlines: c.Bool("gridlines"),
+		PaperSize: c.String("paper-size"),
+	}
+	if c.IsSet("sheet") {
+		opts.Sheet, err = sheetGid(id, c.String("sheet"))
+		if err != nil {
+			return err
+		}
+	}
+	if out := c.String("output"); out != "" && out != "-" {
+		file, err := driveSvc.DownloadToFile(id, out, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Downloaded %s to %s\n", file.Name, out)
+		return nil
+	}
+	_, err = driveSvc.Download(id, c.App.Writer, opts)
+	return err
+}
+
+// sheetGid returns the gid of the sheet with the title (or gid) 'sheet' in
+// the spreadsheet doc identified by 'id'
+func sheetGid(id, sheet string) (string, error) {
+	gid, err := sheetSvc.SheetFromTitle(id, sheet)
+	if err != nil {
+		return "", err
+	}
+	if gid != nil {
+		return strconv.FormatInt(*gid, 10), nil
+	}
+	n, err := strconv.ParseInt(sheet, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("No sheet titled %s", sheet)
+	}
+	title, err := sheetSvc.TitleFromSheetId(id, n)
+	if err != nil {
+		return "", err
+	}
+	if title == nil {
+		return "", fmt.Errorf("No sheet titled %s or with id %d", sheet, n)
+	}
+	return sheet, nil
 }
 
 func infoAction(c *cli.Context) error {
 	if c.NArg() < 1 {
 		return errors.New("FILE_ID is required")
 	}
-	id := c.Args().Get(0)
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
 	file, err := driveSvc.GetInfo(id)
 	if err != nil {
 		return err
@@ -94,25 +229,392 @@ func uploadAction(c *cli.Context) error {
 	if name == "" {
 		return errors.New("Must specify --name if FILE is not given a path")
 	}
-	file, err := driveSvc.CreateOrUpdateFile(name, c.String("parent"), inFile)
+	opts := &gdrive.UploadOptions{
+		Convert:   c.Bool("convert"),
+		NoConvert: c.Bool("no-convert"),
+		ChunkSize: int64(c.Float64("chunk-size") * (1 << 20)),
+		Retries:   c.Int("retries"),
+	}
+	if inFile != nil && !c.Bool("quiet") {
+		opts.Progress = func(sent, total int64) {
+			fmt.Fprintf(c.App.ErrWriter, "\rUploaded %s", progress(sent, total))
+		}
+	}
+	file, err := driveSvc.UploadFile(name, c.String("parent"), inFile, opts)
+	if opts.Progress != nil {
+		fmt.Fprintln(c.App.ErrWriter)
+	}
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(c.App.ErrWriter, "Uploaded file as %s\n", file.Id)
+	fmt.Fprintf(c.App.ErrWriter, "Uploaded file as %s %s\n", file.Id, file.WebViewLink)
 	return nil
 }
 
+// progress describes how much of a transfer of 'total' bytes (-1 if
+// unknown) is done, eg "1.5 MiB of 6.0 MiB (25%)"
+func progress(done, total int64) string {
+	const mib = 1 << 20
+	if total < 0 {
+		return fmt.Sprintf("%.1f MiB", float64(done)/mib)
+	}
+	percent := int64(100)
+	if total > 0 {
+		percent = done * 100 / total
+	}
+	return fmt.Sprintf("%.1f MiB of %.1f MiB (%d%%)", float64(done)/mib, float64(total)/mib, percent)
+}
+
 func listAction(c *cli.Context) error {
-	var q string
-	p := c.String("parent")
-	if p != "" {
-		q = fmt.Sprintf("'%s' in parents", p)
+	return printFiles(c, func(opts *gdrive.ListOptions, show func(string, *drive.File) error) error {
+		parent := c.String("parent")
+		if c.Bool("recursive") {
+			if parent == "" {
+				parent = "root"
+			}
+			return driveSvc.Walk(parent, opts, show)
+		}
+		q := gdrive.NewQuery()
+		if parent != "" {
+			q.InFolder(parent)
+		}
+		return driveSvc.SearchEach(q.Trashed(false).String(), opts, func(f *drive.File) error {
+			return show(f.Name, f)
+		})
+	})
+}
+
+func searchAction(c *cli.Context) error {
+	q := gdrive.NewQuery()
+	if c.IsSet("name") {
+		q.NameIs(c.String("name"))
 	}
-	files, err := driveSvc.Search(q)
-	if err == nil {
-		for _, f := range files {
-			fmt.Fprintf(c.App.ErrWriter, "%-16s\t%1s\n", f.Name, f.Id)
+	if c.IsSet("name-contains") {
+		q.NameContains(c.String("name-contains"))
+	}
+	if c.IsSet("text") {
+		q.FullText(c.String("text"))
+	}
+	if c.IsSet("type") {
+		q.Kind(c.String("type"))
+	}
+	if c.IsSet("parent") {
+		q.InFolder(c.String("parent"))
+	}
+	for _, flag := range []string{"modified-after", "modified-before"} {
+		if !c.IsSet(flag) {
+			continue
+		}
+		t, err := parseTime(c.String(flag))
+		if err != nil {
+			return fmt.Errorf("Invalid --%s: %v", flag, err)
+		}
+		if flag == "modified-after" {
+			q.ModifiedAfter(t)
+		} else {
+			q.ModifiedBefore(t)
 		}
 	}
-	return err
+	if c.IsSet("owner") {
+		q.Owner(c.String("owner"))
+	}
+	if c.Bool("shared-with-me") {
+		q.SharedWithMe()
+	}
+	q.Trashed(c.Bool("trashed"))
+	if c.IsSet("starred") {
+		q.Starred(c.Bool("starred"))
+	}
+	for _, prop := range c.StringSlice("property") {
+		key, value, ok := strings.Cut(prop, "=")
+		if !ok {
+			return fmt.Errorf("Invalid --property %s: expected KEY=VALUE", prop)
+		}
+		q.AppProperty(key, value)
+	}
+	q.Raw(c.String("query"))
+
+	if c.Bool("show-query") {
+		fmt.Fprintln(c.App.ErrWriter, q)
+	}
+	return printFiles(c, func(opts *gdrive.ListOptions, show func(string, *drive.File) error) error {
+		return driveSvc.SearchEach(q.String(), opts, func(f *drive.File) error {
+			return show(f.Name, f)
+		})
+	})
+}
+
+// parseTime parses a date (2006-01-02, in local time) or an RFC 3339 time
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// printFiles writes the files passed to 'show' by 'each' to stdout in the
+// format chosen by the --long, --fields and --json flags, first applying the
+// --order-by, --max and --fields flags to the ListOptions given to 'each'.
+// 'show' takes the path of each file to print in place of its name.
+func printFiles(c *cli.Context, each func(*gdrive.ListOptions, func(string, *drive.File) error) error) error {
+	opts := &gdrive.ListOptions{
+		OrderBy: c.String("order-by"),
+		Max:     c.Int("max"),
+	}
+	asJSON := c.Bool("json")
+	if c.Bool("long") {
+		opts.Fields = gdrive.LongFields
+	}
+	if c.IsSet("fields") {
+		opts.Fields = nil
+		for _, f := range strings.Split(c.String("fields"), ",") {
+			opts.Fields = append(opts.Fields, strings.TrimSpace(f))
+		}
+		asJSON = true
+	}
+
+	var files []*drive.File
+	tw := newTable(c.App.Writer)
+	err := each(opts, func(p string, f *drive.File) error {
+		switch {
+		case asJSON:
+			files = append(files, f)
+		case c.Bool("long"):
+			fmt.Fprintln(tw, longListing(p, f))
+		default:
+			fmt.Fprintf(c.App.Writer, "%-16s\t%1s\n", p, f.Id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if files == nil {
+			files = []*drive.File{}
+		}
+		return writeJSON(c.App.Writer, files)
+	}
+	return tw.Flush()
+}
+
+// longListing formats a line of 'list -l' output for the file 'f' at path 'p':
+// type, size, modified time, owner, shared, path and id separated by tabs
+func longListing(p string, f *drive.File) string {
+	kind := strings.TrimPrefix(f.MimeType, "application/vnd.google-apps.")
+	size := "-"
+	if f.Size > 0 || !strings.HasPrefix(f.MimeType, "application/vnd.google-apps.") {
+		size = strconv.FormatInt(f.Size, 10)
+	}
+	modified := f.ModifiedTime
+	if t, err := time.Parse(time.RFC3339, f.ModifiedTime); err == nil {
+		modified = t.Local().Format("2006-01-02 15:04")
+	}
+	owner := "-"
+	if len(f.Owners) > 0 {
+		owner = f.Owners[0].EmailAddress
+	}
+	shared := "-"
+	if f.Shared {
+		shared = "shared"
+	}
+	return strings.Join([]string{kind, size, modified, owner, shared, p, f.Id}, "\t")
+}
+
+func shareAddAction(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New("FILE_ID is required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	role := c.String("role")
+	var perms []*drive.Permission
+	for _, email := range c.StringSlice("user") {
+		perms = append(perms, gdrive.User(email, role))
+	}
+	for _, email := range c.StringSlice("group") {
+		perms = append(perms, gdrive.Group(email, role))
+	}
+	if c.String("domain") != "" {
+		perms = append(perms, gdrive.Domain(c.String("domain"), role, c.Bool("discoverable")))
+	}
+	if c.Bool("anyone") {
+		perms = append(perms, gdrive.Anyone(role, c.Bool("discoverable")))
+	}
+	if len(perms) == 0 {
+		return errors.New("One of --user, --group, --domain or --anyone is required")
+	}
+	for _, p := range perms {
+		perm, err := driveSvc.Share(id, p, !c.Bool("no-notify"), c.String("message"))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Shared %s with %s as %s (permission %s)\n",
+			id, permissionGrantee(perm), perm.Role, perm.Id)
+	}
+	return nil
+}
+
+// permissionGrantee describes who a permission applies to
+func permissionGrantee(p *drive.Permission) string {
+	switch p.Type {
+	case "domain":
+		return p.Domain
+	case "anyone":
+		return "anyone"
+	}
+	return p.EmailAddress
+}
+
+func shareListAction(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New("FILE_ID is required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	perms, err := driveSvc.Permissions(id)
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		return writeJSON(c.App.Writer, perms)
+	}
+	tw := newTable(c.App.Writer)
+	for _, p := range perms {
+		var notes []string
+		if p.PendingOwner {
+			notes = append(notes, "pending owner")
+		}
+		for _, d := range p.PermissionDetails {
+			if d.Inherited {
+				notes = append(notes, "inherited")
+				break
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", p.Id, p.Type, p.Role, permissionGrantee(p), strings.Join(notes, ","))
+	}
+	return tw.Flush()
+}
+
+func shareUpdateAction(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New("FILE_ID and WHO are required")
+	}
+	if c.String("role") == "" {
+		return errors.New("The --role flag is required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	perm, err := driveSvc.FindPermission(id, c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	perm, err = driveSvc.UpdatePermission(id, perm.Id, c.String("role"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "%s is now %s of %s\n", permissionGrantee(perm), perm.Role, id)
+	return nil
+}
+
+func shareRevokeAction(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New("FILE_ID and WHO are required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	for _, who := range c.Args().Tail() {
+		perm, err := driveSvc.FindPermission(id, who)
+		if err != nil {
+			return err
+		}
+		if err := driveSvc.RevokePermission(id, perm.Id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Revoked %s's access to %s\n", permissionGrantee(perm), id)
+	}
+	return nil
+}
+
+func shareTransferAction(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New("FILE_ID and EMAIL are required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	email := c.Args().Get(1)
+	if _, err := driveSvc.TransferOwnership(id, email); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Transferred ownership of %s to %s\n", id, email)
+	return nil
+}
+
+func mvAction(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New("FILE_ID and FOLDER_ID are required")
+	}
+	args, err := fileArgs(c)
+	if err != nil {
+		return err
+	}
+	parent := args[len(args)-1]
+	for _, id := range args[:len(args)-1] {
+		file, err := driveSvc.MoveFile(id, parent)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Moved %s (%s) to %s\n", file.Name, file.Id, parent)
+	}
+	return nil
+}
+
+func cpAction(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New("FILE_ID is required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	file, err := driveSvc.CopyFile(id, c.String("name"), c.String("parent"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Copied file as %s named %s\n", file.Id, file.Name)
+	return nil
+}
+
+func renameAction(c *cli.Context) error {
+	if c.NArg() < 1 {
+		return errors.New("FILE_ID is required")
+	}
+	meta := &drive.File{Name: c.Args().Get(1)}
+	if c.IsSet("description") {
+		meta.Description = c.String("description")
+		meta.ForceSendFields = []string{"Description"}
+	}
+	if meta.Name == "" && !c.IsSet("description") {
+		return errors.New("NAME or --description is required")
+	}
+	id, err := resolveId(c.Args().First())
+	if err != nil {
+		return err
+	}
+	file, err := driveSvc.UpdateMetadata(id, meta)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Updated %s (%s)\n", file.Name, file.Id)
+	return nil
 }
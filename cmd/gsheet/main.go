@@ -30,6 +30,7 @@ var (
 
 func main() {
 	app.EnableBashCompletion = true
+	withPathFlags(app.Commands)
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Fatal(err)
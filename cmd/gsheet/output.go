@@ -0,0 +1,24 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// writeJSON writes 'v' to 'w' as indented json
+func writeJSON(w io.Writer, v interface{}) error {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(jsonBytes))
+	return nil
+}
+
+// newTable returns a tabwriter for printing aligned columns to 'w'
+// (remember to call Flush)
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
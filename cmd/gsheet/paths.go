@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// isPath reports whether a FILE_ID, --id or --parent value is a drive path
+// ("path:Reports/sales", or anything containing a slash since drive ids never
+// do) rather than an id
+func isPath(s string) bool {
+	return strings.HasPrefix(s, "path:") || strings.Contains(s, "/")
+}
+
+// resolveId returns the id of the drive file at 's' if it is a path;
+// otherwise it returns 's' unchanged
+func resolveId(s string) (string, error) {
+	if !isPath(s) {
+		return s, nil
+	}
+	file, err := driveSvc.ResolvePath(strings.TrimPrefix(s, "path:"))
+	if err != nil {
+		return "", err
+	}
+	return file.Id, nil
+}
+
+// fileArgs returns the positional FILE_ID arguments of the command with any
+// paths resolved to ids
+func fileArgs(c *cli.Context) ([]string, error) {
+	ids := c.Args().Slice()
+	for i, arg := range ids {
+		id, err := resolveId(arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// resolveFlagPaths is a Before hook which replaces paths given to the --id
+// and --parent flags with the ids of the files they name
+func resolveFlagPaths(c *cli.Context) error {
+	for _, flag := range c.Command.Flags {
+		for _, name := range flag.Names() {
+			if name != "id" && name != "parent" {
+				continue
+			}
+			if !isPath(c.String(name)) {
+				continue
+			}
+			id, err := resolveId(c.String(name))
+			if err != nil {
+				return err
+			}
+			if err := c.Set(name, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// withPathFlags installs resolveFlagPaths on 'cmds' and all of their
+// subcommands
+func withPathFlags(cmds []*cli.Command) {
+	for _, cmd := range cmds {
+		before := cmd.Before
+		cmd.Before = func(c *cli.Context) error {
+			if err := resolveFlagPaths(c); err != nil {
+				return err
+			}
+			if before != nil {
+				return before(c)
+			}
+			return nil
+		}
+		withPathFlags(cmd.Subcommands)
+	}
+}
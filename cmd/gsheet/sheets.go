@@ -1,12 +1,16 @@
 package main
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 
+	"github.com/cristoper/gsheet/gsheets"
 	"github.com/urfave/cli/v2"
 )
 
@@ -21,7 +25,36 @@ func deleteSheetAction(c *cli.Context) error {
 	if c.String("id") == "" {
 		return fmt.Errorf("The --id flag is required")
 	}
-	return sheetSvc.DeleteSheet(c.String("id"), c.String("name"))
+	name, err := sheetName(c)
+	if err != nil {
+		return err
+	}
+	return sheetSvc.DeleteSheet(c.String("id"), name)
+}
+
+// sheetName returns the sheet title given by --name, or the title of the sheet
+// found by the --meta selector if it is set
+func sheetName(c *cli.Context) (string, error) {
+	if !c.IsSet("meta") {
+		return c.String("name"), nil
+	}
+	key, value := gsheets.ParseMetadataSelector(c.String("meta"))
+	return sheetSvc.SheetFromMetadata(c.String("id"), key, value)
+}
+
+// sheetRange returns the range given by --range. If the --meta selector is set
+// the range it finds is used instead; if that is a whole sheet then any
+// --range is taken to be relative to that sheet (see gsheets.RelativeRange).
+func sheetRange(c *cli.Context) (string, error) {
+	if !c.IsSet("meta") {
+		return c.String("range"), nil
+	}
+	key, value := gsheets.ParseMetadataSelector(c.String("meta"))
+	a1, err := sheetSvc.RangeFromMetadata(c.String("id"), key, value)
+	if err != nil {
+		return "", err
+	}
+	return gsheets.RelativeRange(a1, c.String("range"))
 }
 
 func titleByIdAction(c *cli.Context) error {
@@ -44,6 +77,26 @@ func titleByIdAction(c *cli.Context) error {
 	return nil
 }
 
+func listSheetsAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	infos, err := sheetSvc.ListSheets(c.String("id"))
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		return writeJSON(c.App.Writer, infos)
+	}
+	tw := newTable(c.App.Writer)
+	fmt.Fprintln(tw, "ID\tTITLE\tINDEX\tROWS\tCOLS\tFROZEN\tHIDDEN\tTAB COLOR")
+	for _, s := range infos {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%d\t%d,%d\t%t\t%s\n", s.Id, s.Title, s.Index,
+			s.Rows, s.Columns, s.FrozenRows, s.FrozenColumns, s.Hidden, s.TabColor)
+	}
+	return tw.Flush()
+}
+
 func sheetInfoAction(c *cli.Context) error {
 	if c.NArg() < 1 {
 		return errors.New("SHEET_ID is required")
@@ -66,10 +119,522 @@ func clearSheetAction(c *cli.Context) error {
 }
 
 func sortSheetAction(c *cli.Context) error {
-	return sheetSvc.Sort(c.String("id"), c.String("name"), c.Bool("ascending"),
+	name, err := sheetName(c)
+	if err != nil {
+		return err
+	}
+	return sheetSvc.Sort(c.String("id"), name, c.Bool("ascending"),
 		c.Int64("column"))
 }
 
+func formatAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	format := &gsheets.CellFormat{
+		NumberFormat:        c.String("number-format"),
+		NumberType:          c.String("number-type"),
+		ForegroundColor:     c.String("fg"),
+		BackgroundColor:     c.String("bg"),
+		HorizontalAlignment: c.String("halign"),
+		VerticalAlignment:   c.String("valign"),
+		WrapStrategy:        c.String("wrap"),
+	}
+	if c.IsSet("bold") {
+		bold := c.Bool("bold")
+		format.Bold = &bold
+	}
+	if c.IsSet("italic") {
+		italic := c.Bool("italic")
+		format.Italic = &italic
+	}
+	if c.IsSet("border") || c.IsSet("border-color") {
+		format.Borders = &gsheets.Border{
+			Style: c.String("border"),
+			Color: c.String("border-color"),
+		}
+		if format.Borders.Style == "" {
+			format.Borders.Style = "SOLID"
+		}
+	}
+	return sheetSvc.FormatRange(c.String("id"), c.String("range"), format)
+}
+
+func validateAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	if c.Bool("clear") {
+		return sheetSvc.ClearValidation(c.String("id"), c.String("range"))
+	}
+
+	var v *gsheets.Validation
+	switch {
+	case c.IsSet("one-of"):
+		v = gsheets.OneOf(c.StringSlice("one-of")...)
+	case c.IsSet("one-of-range"):
+		v = gsheets.OneOfRange(c.String("one-of-range"))
+	case c.Bool("checkbox"):
+		v = gsheets.Checkbox()
+	case c.IsSet("formula"):
+		v = gsheets.CustomFormula(c.String("formula"))
+	case c.IsSet("condition"):
+		v = &gsheets.Validation{
+			Condition: c.String("condition"),
+			Values:    c.StringSlice("value"),
+		}
+	default:
+		return errors.New("One of --one-of, --one-of-range, --checkbox, --formula, --condition or --clear is required")
+	}
+	v.Strict = c.Bool("strict")
+	v.InputMessage = c.String("message")
+	return sheetSvc.SetValidation(c.String("id"), c.String("range"), v)
+}
+
+func namedRangeListAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	ranges, err := sheetSvc.NamedRanges(c.String("id"))
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		return writeJSON(c.App.Writer, ranges)
+	}
+	tw := newTable(c.App.Writer)
+	for _, nr := range ranges {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", nr.Name, nr.Range, nr.Id)
+	}
+	return tw.Flush()
+}
+
+func namedRangeAddAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	nr, err := sheetSvc.AddNamedRange(c.String("id"), c.String("name"), c.String("range"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Created named range %s covering %s\n", nr.Name, nr.Range)
+	return nil
+}
+
+func namedRangeUpdateAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	return sheetSvc.UpdateNamedRange(c.String("id"), c.String("name"),
+		c.String("new-name"), c.String("range"))
+}
+
+func namedRangeDeleteAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	return sheetSvc.DeleteNamedRange(c.String("id"), c.String("name"))
+}
+
+func protectionListAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	protections, err := sheetSvc.Protections(c.String("id"))
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		return writeJSON(c.App.Writer, protections)
+	}
+	tw := newTable(c.App.Writer)
+	for _, p := range protections {
+		editors := strings.Join(append(p.Users, p.Groups...), ",")
+		switch {
+		case p.WarningOnly:
+			editors = "(warning only)"
+		case p.DomainUsersCanEdit:
+			editors = strings.TrimPrefix(editors+",(domain)", ",")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", p.Id, p.Range, editors, p.Description)
+	}
+	return tw.Flush()
+}
+
+// protectionFromFlags reads the protection options shared by the 'protection
+// add' and 'protection update' commands
+func protectionFromFlags(c *cli.Context) *gsheets.Protection {
+	return &gsheets.Protection{
+		Description:        c.String("description"),
+		WarningOnly:        c.Bool("warning-only"),
+		Users:              c.StringSlice("user"),
+		Groups:             c.StringSlice("group"),
+		DomainUsersCanEdit: c.Bool("domain"),
+		Unprotected:        c.StringSlice("unprotected"),
+	}
+}
+
+func protectionAddAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	p, err := sheetSvc.Protect(c.String("id"), c.String("range"), protectionFromFlags(c))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Protected %s with id %d\n", p.Range, p.Id)
+	return nil
+}
+
+func protectionUpdateAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if !c.IsSet("protection-id") {
+		return fmt.Errorf("The --protection-id flag is required")
+	}
+	return sheetSvc.UpdateProtection(c.String("id"), c.Int64("protection-id"),
+		c.String("range"), protectionFromFlags(c), protectionFields(c))
+}
+
+// protectionFields returns the options of a protection to change for the
+// protection flags which were given
+func protectionFields(c *cli.Context) []string {
+	var fields []string
+	if c.IsSet("description") {
+		fields = append(fields, "description")
+	}
+	editors := c.IsSet("user") || c.IsSet("group") || c.IsSet("domain")
+	if c.IsSet("warning-only") || editors {
+		// a protection has either editors or a warning, so changing one
+		// clears the other
+		fields = append(fields, "warningOnly")
+	}
+	if editors || c.Bool("warning-only") {
+		fields = append(fields, "editors")
+	}
+	if c.IsSet("unprotected") {
+		fields = append(fields, "unprotectedRanges")
+	}
+	return fields
+}
+
+func protectionRemoveAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if !c.IsSet("protection-id") {
+		return fmt.Errorf("The --protection-id flag is required")
+	}
+	return sheetSvc.Unprotect(c.String("id"), c.Int64("protection-id"))
+}
+
+func freezeAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	return sheetSvc.Freeze(c.String("id"), c.String("name"), c.Int64("rows"), c.Int64("cols"))
+}
+
+func resizeAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	switch {
+	case c.Bool("auto"):
+		return sheetSvc.AutoResize(c.String("id"), c.String("range"))
+	case c.IsSet("size"):
+		return sheetSvc.Resize(c.String("id"), c.String("range"), c.Int64("size"))
+	}
+	return errors.New("One of --size or --auto is required")
+}
+
+func hideAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	return sheetSvc.Hide(c.String("id"), c.String("range"), !c.Bool("unhide"))
+}
+
+func groupAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	switch {
+	case c.Bool("ungroup"):
+		return sheetSvc.Ungroup(c.String("id"), c.String("range"))
+	case c.IsSet("collapse"):
+		return sheetSvc.CollapseGroup(c.String("id"), c.String("range"), c.Bool("collapse"))
+	}
+	return sheetSvc.Group(c.String("id"), c.String("range"))
+}
+
+func mergeAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	switch {
+	case c.Bool("list"):
+		merges, err := sheetSvc.MergedRanges(c.String("id"), c.String("range"))
+		if err != nil {
+			return err
+		}
+		for _, m := range merges {
+			fmt.Fprintln(c.App.Writer, m)
+		}
+		return nil
+	case c.Bool("unmerge"):
+		return sheetSvc.UnmergeCells(c.String("id"), c.String("range"))
+	}
+	mergeTypes := map[string]string{
+		"all":     gsheets.MergeAll,
+		"columns": gsheets.MergeColumns,
+		"rows":    gsheets.MergeRows,
+	}
+	mergeType, ok := mergeTypes[strings.ToLower(c.String("type"))]
+	if !ok {
+		return fmt.Errorf("Unknown merge --type: %s", c.String("type"))
+	}
+	return sheetSvc.MergeCells(c.String("id"), c.String("range"), mergeType)
+}
+
+func chartListAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	charts, err := sheetSvc.Charts(c.String("id"))
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		return writeJSON(c.App.Writer, charts)
+	}
+	tw := newTable(c.App.Writer)
+	for _, chart := range charts {
+		pos := chart.Anchor
+		if chart.OwnSheet {
+			pos = chart.SheetTitle
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", chart.Id, chart.Type, chart.Source, pos, chart.Title)
+	}
+	return tw.Flush()
+}
+
+func chartAddAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("spec") == "" {
+		return fmt.Errorf("The --spec flag is required")
+	}
+	var spec gsheets.ChartSpec
+	if err := readSpec(c.String("spec"), &spec); err != nil {
+		return err
+	}
+	chartId, err := sheetSvc.AddChart(c.String("id"), &spec)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Created chart with id %d\n", chartId)
+	return nil
+}
+
+func chartUpdateAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if !c.IsSet("chart-id") {
+		return fmt.Errorf("The --chart-id flag is required")
+	}
+	if c.String("spec") == "" {
+		return fmt.Errorf("The --spec flag is required")
+	}
+	var spec gsheets.ChartSpec
+	if err := readSpec(c.String("spec"), &spec); err != nil {
+		return err
+	}
+	return sheetSvc.UpdateChart(c.String("id"), c.Int64("chart-id"), &spec)
+}
+
+func chartDeleteAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if !c.IsSet("chart-id") {
+		return fmt.Errorf("The --chart-id flag is required")
+	}
+	return sheetSvc.DeleteChart(c.String("id"), c.Int64("chart-id"))
+}
+
+func pivotAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("spec") == "" {
+		return fmt.Errorf("The --spec flag is required")
+	}
+	var spec gsheets.PivotSpec
+	if err := readSpec(c.String("spec"), &spec); err != nil {
+		return err
+	}
+	return sheetSvc.CreatePivot(c.String("id"), &spec)
+}
+
+func metadataSetAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	md, err := sheetSvc.SetMetadata(c.String("id"), c.String("range"), c.String("key"), c.String("value"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Set metadata %s=%s on %s with id %d\n", md.Key, md.Value, md.Location, md.Id)
+	return nil
+}
+
+func metadataSearchAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	found, err := sheetSvc.SearchMetadata(c.String("id"), c.String("key"), c.String("value"))
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		return writeJSON(c.App.Writer, found)
+	}
+	tw := newTable(c.App.Writer)
+	for _, md := range found {
+		fmt.Fprintf(tw, "%d\t%s=%s\t%s\n", md.Id, md.Key, md.Value, md.Location)
+	}
+	return tw.Flush()
+}
+
+func metadataDeleteAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("key") == "" {
+		return fmt.Errorf("The --key flag is required")
+	}
+	n, err := sheetSvc.DeleteMetadata(c.String("id"), c.String("key"), c.String("value"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Deleted %d metadata entries\n", n)
+	return nil
+}
+
+// readCellCSV reads 'cell,value...' records from the csv file at 'path' ('-'
+// for stdin)
+func readCellCSV(path string) ([][]string, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	csvR := csv.NewReader(in)
+	csvR.FieldsPerRecord = -1
+	records, err := csvR.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for i, rec := range records {
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("Line %d of %s: expected at least 2 fields", i+1, path)
+		}
+	}
+	return records, nil
+}
+
+func noteAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	switch {
+	case c.IsSet("csv"):
+		records, err := readCellCSV(c.String("csv"))
+		if err != nil {
+			return err
+		}
+		notes := make([]gsheets.CellNote, len(records))
+		for i, rec := range records {
+			notes[i] = gsheets.CellNote{Cell: rec[0], Note: rec[1]}
+		}
+		if err := sheetSvc.SetNotes(c.String("id"), notes); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Set %d notes\n", len(notes))
+		return nil
+	case c.String("range") == "":
+		return fmt.Errorf("The --range flag is required")
+	case c.IsSet("set"):
+		return sheetSvc.SetNote(c.String("id"), c.String("range"), c.String("set"))
+	}
+
+	notes, err := sheetSvc.GetNotes(c.String("id"), c.String("range"))
+	if err != nil {
+		return err
+	}
+	csvW := csv.NewWriter(c.App.Writer)
+	for _, n := range notes {
+		csvW.Write([]string{n.Cell, n.Note})
+	}
+	csvW.Flush()
+	return csvW.Error()
+}
+
+func linkAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.IsSet("csv") {
+		records, err := readCellCSV(c.String("csv"))
+		if err != nil {
+			return err
+		}
+		links := make([]gsheets.CellLink, len(records))
+		for i, rec := range records {
+			links[i] = gsheets.CellLink{Cell: rec[0]}
+			links[i].URI = rec[1]
+			if len(rec) > 2 {
+				links[i].Text = rec[2]
+			}
+		}
+		if err := sheetSvc.SetHyperlinks(c.String("id"), links); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.ErrWriter, "Set %d links\n", len(links))
+		return nil
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	if c.String("url") == "" {
+		return fmt.Errorf("The --url flag is required")
+	}
+	return sheetSvc.SetHyperlink(c.String("id"), c.String("range"), gsheets.Hyperlink{
+		URI:  c.String("url"),
+		Text: c.String("text"),
+	})
+}
+
 func rangeSheetAction(c *cli.Context) error {
 	info, err := os.Stdin.Stat()
 	if err != nil {
@@ -84,28 +649,42 @@ func rangeSheetAction(c *cli.Context) error {
 	}
 	sheetSvc.Sep = rune(sep[0])
 
+	a1Range, err := sheetRange(c)
+	if err != nil {
+		return err
+	}
+
 	forceRead := c.Bool("read")
 	if forceRead || info.Mode()&os.ModeCharDevice != 0 {
 		// stdin is not connected to a pipe or file
 		// get data
-		vals, err := sheetSvc.GetRangeCSV(c.String("id"), c.String("range"))
+		vals, err := sheetSvc.GetRangeCSV(c.String("id"), a1Range)
 		if err != nil {
 			return err
 		}
 		fmt.Println(string(vals))
+		if c.Bool("merges") {
+			merges, err := sheetSvc.MergedRanges(c.String("id"), a1Range)
+			if err != nil {
+				return err
+			}
+			for _, m := range merges {
+				fmt.Fprintf(os.Stderr, "Merged: %s\n", m)
+			}
+		}
 	} else {
 		// otherwise stdin is connected to a pipe or file
 		// send data
 		if c.Bool("append") {
 			// append
-			resp, err := sheetSvc.AppendRangeCSV(c.String("id"), c.String("range"), os.Stdin)
+			resp, err := sheetSvc.AppendRangeCSV(c.String("id"), a1Range, os.Stdin)
 			if err != nil {
 				return err
 			}
 			fmt.Printf("Updated %d cells\n", resp.Updates.UpdatedCells)
 		} else {
 			// overwrite
-			resp, err := sheetSvc.UpdateRangeCSV(c.String("id"), c.String("range"), os.Stdin)
+			resp, err := sheetSvc.UpdateRangeCSV(c.String("id"), a1Range, os.Stdin)
 			if err != nil {
 				return err
 			}
@@ -114,3 +693,137 @@ func rangeSheetAction(c *cli.Context) error {
 	}
 	return nil
 }
+
+func schemaExportAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	schema, err := sheetSvc.ExportSchema(c.String("id"))
+	if err != nil {
+		return err
+	}
+	return writeSpec(c.App.Writer, schema)
+}
+
+func schemaApplyAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("spec") == "" {
+		return fmt.Errorf("The --spec flag is required")
+	}
+	var schema gsheets.Schema
+	if err := readSpec(c.String("spec"), &schema); err != nil {
+		return err
+	}
+	changes, err := sheetSvc.PlanSchema(c.String("id"), &schema)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(c.App.ErrWriter, "No changes; the spreadsheet matches the schema")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Fprintln(c.App.Writer, change)
+	}
+	if !c.Bool("yes") {
+		fmt.Fprintf(c.App.ErrWriter, "Planned %d changes; use --yes to make them\n", len(changes))
+		return nil
+	}
+	if err := sheetSvc.ApplySchema(c.String("id"), changes); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Applied %d changes\n", len(changes))
+	return nil
+}
+
+func cleanDedupeAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	removed, err := sheetSvc.DeleteDuplicates(c.String("id"), c.String("range"), c.StringSlice("columns")...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Removed %d duplicate rows\n", removed)
+	return nil
+}
+
+func cleanTrimAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	changed, err := sheetSvc.TrimWhitespace(c.String("id"), c.String("range"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Trimmed %d cells\n", changed)
+	return nil
+}
+
+func cleanSplitAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("range") == "" {
+		return fmt.Errorf("The --range flag is required")
+	}
+	cells, err := sheetSvc.TextToColumns(c.String("id"), c.String("range"), c.String("delimiter"))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.ErrWriter, "Split %d cells containing the delimiter\n", cells)
+	return nil
+}
+
+// pasteTypes maps --paste names to gsheets paste types
+var pasteTypes = map[string]string{
+	"normal":      gsheets.PasteNormal,
+	"values":      gsheets.PasteValues,
+	"formats":     gsheets.PasteFormat,
+	"formulas":    gsheets.PasteFormula,
+	"no-borders":  gsheets.PasteNoBorders,
+	"validation":  gsheets.PasteValidation,
+	"conditional": gsheets.PasteConditionalFormat,
+}
+
+func copyRangeAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("from") == "" {
+		return fmt.Errorf("The --from flag is required")
+	}
+	if c.IsSet("fill") {
+		return sheetSvc.AutoFillFrom(c.String("id"), c.String("from"), "ROWS", c.Int64("fill"))
+	}
+	if c.String("to") == "" {
+		return fmt.Errorf("The --to flag (or --fill) is required")
+	}
+	paste, ok := pasteTypes[strings.ToLower(c.String("paste"))]
+	if !ok {
+		return fmt.Errorf("Unknown --paste type: %s", c.String("paste"))
+	}
+	return sheetSvc.CopyRange(c.String("id"), c.String("from"), c.String("to"), paste, c.Bool("transpose"))
+}
+
+func moveRangeAction(c *cli.Context) error {
+	if c.String("id") == "" {
+		return fmt.Errorf("The --id flag is required")
+	}
+	if c.String("from") == "" || c.String("to") == "" {
+		return fmt.Errorf("The --from and --to flags are required")
+	}
+	paste, ok := pasteTypes[strings.ToLower(c.String("paste"))]
+	if !ok {
+		return fmt.Errorf("Unknown --paste type: %s", c.String("paste"))
+	}
+	return sheetSvc.MoveRange(c.String("id"), c.String("from"), c.String("to"), paste)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readSpec decodes the yaml (or json) file at 'path' into 'v'.
+// A path of "-" reads from stdin.
+func readSpec(path string, v interface{}) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	dec := yaml.NewDecoder(in)
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
+
+// writeSpec encodes 'v' as yaml to 'w'
+func writeSpec(w io.Writer, v interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
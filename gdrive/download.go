@@ -0,0 +1,222 @@
+package gdrive
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// downloadFields are the fields needed to download (and check) a file
+const downloadFields = "id, name, mimeType, size, md5Checksum"
+
+// Download writes the contents of the file identified by 'id' to 'w' as they
+// arrive, without holding the file in memory, and returns the file's
+// metadata. Google Workspace files are exported as set by 'opts' (see
+// DownloadFileAs; it may be nil).
+// Other files are checked against the md5Checksum Drive keeps for them, and
+// if the connection fails part way the download resumes where it stopped.
+func (svc *Service) Download(id string, w io.Writer, opts *ExportOptions) (*drive.File, error) {
+	file, err := svc.downloadInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceKind(file.MimeType) != "" {
+		return file, svc.export(id, w, opts)
+	}
+	if opts != nil && opts.Format != "" {
+		return nil, fmt.Errorf("%s is not a Google Workspace file, so cannot be exported as %s", file.Name, opts.Format)
+	}
+	h := md5.New()
+	if err := svc.stream(file, io.MultiWriter(w, h), 0); err != nil {
+		return nil, err
+	}
+	return file, verify(file, h)
+}
+
+// DownloadToFile downloads the file identified by 'id' (see Download) to
+// 'path'. The contents are written to 'path' + ".part", which is renamed to
+// 'path' once the download is complete and verified; if a ".part" file is
+// left by a download which was interrupted it is resumed, rather than
+// starting over (except for exported Google Workspace files).
+func (svc *Service) DownloadToFile(id, path string, opts *ExportOptions) (*drive.File, error) {
+	file, err := svc.downloadInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	part := path + ".part"
+	out, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	if workspaceKind(file.MimeType) != "" {
+		if err := out.Truncate(0); err != nil {
+			return nil, err
+		}
+		if err := svc.export(id, out, opts); err != nil {
+			return nil, err
+		}
+	} else {
+		if opts != nil && opts.Format != "" {
+			return nil, fmt.Errorf("%s is not a Google Workspace file, so cannot be exported as %s", file.Name, opts.Format)
+		}
+		// hash what we already have, then resume after it
+		h := md5.New()
+		have, err := io.Copy(h, out)
+		if err != nil {
+			return nil, err
+		}
+		if have > file.Size {
+			// not a partial download of this file after all
+			if err := out.Truncate(0); err != nil {
+				return nil, err
+			}
+			h.Reset()
+			have = 0
+		}
+		if _, err := out.Seek(have, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := svc.stream(file, io.MultiWriter(out, h), have); err != nil {
+			return nil, err
+		}
+		if err := verify(file, h); err != nil {
+			// start over next time
+			out.Close()
+			os.Remove(part)
+			return nil, err
+		}
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	return file, os.Rename(part, path)
+}
+
+// downloadInfo returns the metadata needed to download the file identified
+// by 'id'
+func (svc *Service) downloadInfo(id string) (*drive.File, error) {
+	return svc.filer.Get(id).Fields(downloadFields).SupportsAllDrives(true).Context(svc.ctx).Do()
+}
+
+// export writes the Google Workspace file identified by 'id' to 'w' as set
+// by 'opts'
+func (svc *Service) export(id string, w io.Writer, opts *ExportOptions) error {
+	resp, err := svc.DownloadFileAs(id, opts)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// verify checks the md5 hash 'h' of the downloaded contents of 'file'
+// against its md5Checksum (if Drive has one for it)
+func verify(file *drive.File, h hash.Hash) error {
+	if file.Md5Checksum == "" {
+		return nil
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != file.Md5Checksum {
+		return fmt.Errorf("Downloaded %s is corrupt: its md5 checksum is %s, but should be %s",
+			file.Name, sum, file.Md5Checksum)
+	}
+	return nil
+}
+
+// errNoRange is returned if the server ignores the Range of a request to
+// resume a download
+var errNoRange = errors.New("the server cannot resume the download")
+
+// writeError is an error writing downloaded data (which is not retried)
+type writeError struct {
+	error
+}
+
+// stream writes the contents of 'file', from byte 'offset' on, to 'w'. If
+// the download fails it is retried (up to defaultRetries times in a row)
+// with a Range request for the rest of the file.
+func (svc *Service) stream(file *drive.File, w io.Writer, offset int64) error {
+	got := offset
+	failures := 0
+	for {
+		if file.Size > 0 && got >= file.Size {
+			return nil
+		}
+		n, err := svc.streamFrom(file.Id, w, got)
+		got += n
+		if err == nil {
+			return nil
+		}
+		if n > 0 {
+			// made progress, so start counting again
+			failures = 0
+		}
+		if !canResume(err) || failures >= defaultRetries {
+			return err
+		}
+		if err := svc.wait(failures); err != nil {
+			return err
+		}
+		failures++
+	}
+}
+
+// streamFrom writes the contents of the file identified by 'id', from byte
+// 'offset' on, to 'w' and returns the number of bytes written
+func (svc *Service) streamFrom(id string, w io.Writer, offset int64) (int64, error) {
+	call := svc.filer.Get(id).SupportsAllDrives(true).Context(svc.ctx)
+	if offset > 0 {
+		call.Header().Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := call.Download()
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		return 0, errNoRange
+	}
+
+	var written int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return written, writeError{err}
+			}
+			written += int64(n)
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// canResume reports whether a download which failed with 'err' can be
+// retried
+func canResume(err error) bool {
+	var we writeError
+	if errors.As(err, &we) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code >= 500 || gerr.Code == http.StatusTooManyRequests
+	}
+	return err != errNoRange
+}
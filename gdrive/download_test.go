@@ -0,0 +1,156 @@
+package gdrive
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// fakeDownloads is a stand-in for Drive which serves one file, supports
+// Range requests and can drop the connection part way through a download
+type fakeDownloads struct {
+	mu      sync.Mutex
+	content []byte
+	md5     string
+	ranges  []string // Range of each download request
+
+	// drop the connection after this many bytes of the next download
+	cutAfter int
+}
+
+func (f *fakeDownloads) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path != "/files/fileid" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.Query().Get("alt") != "media" {
+		json.NewEncoder(w).Encode(&drive.File{
+			Id:          "fileid",
+			Name:        "data.bin",
+			MimeType:    "application/octet-stream",
+			Size:        int64(len(f.content)),
+			Md5Checksum: f.md5,
+		})
+		return
+	}
+
+	rng := r.Header.Get("Range")
+	f.ranges = append(f.ranges, rng)
+	body := f.content
+	if rng != "" {
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		body = body[start:]
+		w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(f.content)-1)+"/"+strconv.Itoa(len(f.content)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	if f.cutAfter > 0 {
+		// the server closes the connection when the handler writes less
+		// than the Content-Length
+		w.Write(body[:f.cutAfter])
+		f.cutAfter = 0
+		return
+	}
+	w.Write(body)
+}
+
+// newFakeDownloads returns a Service which downloads 'content' from a
+// fakeDownloads server as the file "fileid"
+func newFakeDownloads(t *testing.T, content []byte) (*Service, *fakeDownloads) {
+	sum := md5.Sum(content)
+	fake := &fakeDownloads{content: content, md5: hex.EncodeToString(sum[:])}
+	return newTestService(t, fake), fake
+}
+
+var downloadData = bytes.Repeat([]byte("0123456789abcdef"), 10000)
+
+func TestDownloadResumes(t *testing.T) {
+	svc, fake := newFakeDownloads(t, downloadData)
+	fake.cutAfter = 50000
+	var buf bytes.Buffer
+	file, err := svc.Download("fileid", &buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), downloadData) {
+		t.Errorf("downloaded %d bytes, want %d", buf.Len(), len(downloadData))
+	}
+	if file.Name != "data.bin" {
+		t.Errorf("got file %+v", file)
+	}
+	if len(fake.ranges) != 2 || fake.ranges[1] != "bytes=50000-" {
+		t.Errorf("got requests with ranges %q", fake.ranges)
+	}
+}
+
+func TestDownloadChecksum(t *testing.T) {
+	svc, fake := newFakeDownloads(t, downloadData)
+	fake.md5 = "0123456789abcdef0123456789abcdef"
+	var buf bytes.Buffer
+	if _, err := svc.Download("fileid", &buf, nil); err == nil || !strings.Contains(err.Error(), "md5") {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+}
+
+func TestDownloadToFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.bin")
+
+	t.Run("resume part", func(t *testing.T) {
+		svc, fake := newFakeDownloads(t, downloadData)
+		if err := os.WriteFile(path+".part", downloadData[:1234], 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.DownloadToFile("fileid", path, nil); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, downloadData) {
+			t.Errorf("downloaded %d bytes, want %d", len(got), len(downloadData))
+		}
+		if len(fake.ranges) != 1 || fake.ranges[0] != "bytes=1234-" {
+			t.Errorf("got requests with ranges %q", fake.ranges)
+		}
+		if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("part file left behind: %v", err)
+		}
+	})
+
+	t.Run("corrupt part", func(t *testing.T) {
+		svc, _ := newFakeDownloads(t, downloadData)
+		os.Remove(path)
+		if err := os.WriteFile(path+".part", []byte("not the start of the file"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.DownloadToFile("fileid", path, nil); err == nil {
+			t.Fatal("expected a checksum error")
+		}
+		if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("corrupt part file kept: %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("corrupt file saved: %v", err)
+		}
+		// which lets the next try start over
+		if _, err := svc.DownloadToFile("fileid", path, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package gdrive
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	mimePPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	mimeODS  = "application/vnd.oasis.opendocument.spreadsheet"
+	mimeODT  = "application/vnd.oasis.opendocument.text"
+	mimeODP  = "application/vnd.oasis.opendocument.presentation"
+)
+
+// Map google doc type to the MIME type of each format it can be exported as
+// https://developers.google.com/drive/api/v3/ref-export-formats
+var exportMap = map[string]map[string]string{
+	"spreadsheet": {
+		"csv":  "text/csv",
+		"tsv":  "text/tab-separated-values",
+		"xlsx": mimeXLSX,
+		"ods":  mimeODS,
+		"pdf":  "application/pdf",
+		"zip":  "application/zip", // html files, one per sheet
+		"html": "application/zip",
+	},
+	"document": {
+		"txt":  "text/plain",
+		"docx": mimeDOCX,
+		"odt":  mimeODT,
+		"md":   "text/markdown",
+		"html": "text/html",
+		"pdf":  "application/pdf",
+		"rtf":  "application/rtf",
+		"epub": "application/epub+zip",
+	},
+	"presentation": {
+		"txt":  "text/plain",
+		"pptx": mimePPTX,
+		"odp":  mimeODP,
+		"pdf":  "application/pdf",
+	},
+	"drawing": {
+		"svg": "image/svg+xml",
+		"png": "image/png",
+		"jpg": "image/jpeg",
+		"pdf": "application/pdf",
+	},
+}
+
+// Format each google doc type is exported as if none is given (anything not
+// listed is exported as text/plain)
+var defaultExport = map[string]string{
+	"spreadsheet":  "csv",
+	"document":     "txt",
+	"presentation": "txt",
+	"drawing":      "svg",
+}
+
+// sheetsExportFormats are the formats which can be exported per sheet or
+// with print options by the Sheets export URL
+var sheetsExportFormats = map[string]bool{"csv": true, "tsv": true, "pdf": true}
+
+// ExportOptions control how DownloadFileAs exports Google Workspace files
+type ExportOptions struct {
+	// Format name (eg "xlsx", "pdf", "docx", "md"; see ExportFormats) or a
+	// MIME type. Defaults to csv for Sheets, svg for Drawings and plain
+	// text for anything else.
+	Format string
+
+	// The gid (sheet id) of the sheet of a Sheets doc to export (csv, tsv and
+	// pdf only). Without it, csv and tsv get the first visible sheet and pdf
+	// gets every sheet.
+	Sheet string
+
+	// Print options for exporting Sheets docs as pdf
+	Landscape bool
+	FitWidth  bool   // scale to fit the page width
+	Gridlines bool   // print the cell gridlines
+	PaperSize string // eg "letter", "legal" or "A4"
+}
+
+// printing reports whether any of the pdf print options are set
+func (opts *ExportOptions) printing() bool {
+	return opts.Landscape || opts.FitWidth || opts.Gridlines || opts.PaperSize != ""
+}
+
+// ExportFormats returns the names of the formats a Google Workspace file of
+// MIME type 'mime' can be exported as
+func ExportFormats(mime string) []string {
+	var names []string
+	for name := range exportMap[workspaceKind(mime)] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// workspaceKind returns the kind (eg "spreadsheet") of a Google Workspace
+// MIME type, or "" if it is not one
+func workspaceKind(mime string) string {
+	if !strings.HasPrefix(mime, "application/vnd.google-apps.") {
+		return ""
+	}
+	return strings.TrimPrefix(mime, "application/vnd.google-apps.")
+}
+
+// exportFormat returns the format name and MIME type to export a Google
+// Workspace file of 'kind' as 'format' (a name, a MIME type or empty for the
+// default). The name is empty if 'format' is a MIME type we do not know.
+func exportFormat(kind, format string) (string, string, error) {
+	formats := exportMap[kind]
+	if format == "" {
+		format = defaultExport[kind]
+		if format == "" {
+			return "", "text/plain", nil
+		}
+	}
+	if strings.Contains(format, "/") {
+		for name, mime := range formats {
+			if mime == format {
+				return name, mime, nil
+			}
+		}
+		return "", format, nil
+	}
+	format = strings.ToLower(format)
+	if mime, ok := formats[format]; ok {
+		return format, mime, nil
+	}
+	return "", "", fmt.Errorf("Cannot export a %s as %s (use one of: %s)", kind, format,
+		strings.Join(ExportFormats("application/vnd.google-apps."+kind), ", "))
+}
+
+// sheetsExportURL returns the URL which exports the Sheets doc identified by
+// 'id' as 'format' (csv, tsv or pdf) with the Sheet and print options in
+// 'opts'
+func sheetsExportURL(id, format string, opts *ExportOptions) string {
+	params := url.Values{"format": {format}}
+	if opts.Sheet != "" {
+		params.Set("gid", opts.Sheet)
+	}
+	if format == "pdf" {
+		params.Set("portrait", fmt.Sprint(!opts.Landscape))
+		params.Set("fitw", fmt.Sprint(opts.FitWidth))
+		params.Set("gridlines", fmt.Sprint(opts.Gridlines))
+		if opts.PaperSize != "" {
+			params.Set("size", strings.ToLower(opts.PaperSize))
+		}
+	}
+	return fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/export?%s", url.PathEscape(id), params.Encode())
+}
+
+// exportSheets exports the Sheets doc identified by 'id' with the Sheets
+// export URL, which (unlike the Drive API) can export a single sheet and
+// takes print options
+func (svc *Service) exportSheets(id, format string, opts *ExportOptions) (*http.Response, error) {
+	if !sheetsExportFormats[format] {
+		return nil, fmt.Errorf("A single sheet or print options can only be exported as csv, tsv or pdf, not %s", format)
+	}
+	if opts.printing() && format != "pdf" {
+		return nil, errors.New("Print options only apply to pdf exports")
+	}
+	req, err := http.NewRequestWithContext(svc.ctx, "GET", sheetsExportURL(id, format, opts), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := svc.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := googleapi.CheckResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,52 @@
+package gdrive
+
+import (
+	"testing"
+)
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		kind, format       string
+		wantName, wantMime string
+		wantErr            bool
+	}{
+		{"spreadsheet", "", "csv", "text/csv", false},
+		{"spreadsheet", "XLSX", "xlsx", mimeXLSX, false},
+		{"spreadsheet", "text/tab-separated-values", "tsv", "text/tab-separated-values", false},
+		{"spreadsheet", "docx", "", "", true},
+		{"document", "md", "md", "text/markdown", false},
+		{"presentation", "pptx", "pptx", mimePPTX, false},
+		{"presentation", "", "txt", "text/plain", false},
+		{"form", "", "", "text/plain", false},
+		{"form", "application/zip", "", "application/zip", false},
+	}
+	for _, test := range tests {
+		name, mime, err := exportFormat(test.kind, test.format)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s as %q: unexpected error: %v", test.kind, test.format, err)
+			continue
+		}
+		if name != test.wantName || mime != test.wantMime {
+			t.Errorf("%s as %q: got %q, %q; want %q, %q", test.kind, test.format, name, mime, test.wantName, test.wantMime)
+		}
+	}
+}
+
+func TestSheetsExportURL(t *testing.T) {
+	base := "https://docs.google.com/spreadsheets/d/abc/export?"
+	tests := []struct {
+		format string
+		opts   ExportOptions
+		want   string
+	}{
+		{"csv", ExportOptions{Sheet: "42"}, base + "format=csv&gid=42"},
+		{"pdf", ExportOptions{}, base + "fitw=false&format=pdf&gridlines=false&portrait=true"},
+		{"pdf", ExportOptions{Sheet: "0", Landscape: true, FitWidth: true, Gridlines: true, PaperSize: "A4"},
+			base + "fitw=true&format=pdf&gid=0&gridlines=true&portrait=false&size=a4"},
+	}
+	for _, test := range tests {
+		if got := sheetsExportURL("abc", test.format, &test.opts); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
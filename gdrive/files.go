@@ -2,32 +2,32 @@
 // search, upload, download, delete) files on Google Drive.
 // It has special handling for .csv files which it uploads as a Google Sheets
 // documents (and downloads the first visible sheet of Google Sheets documents
-// as .csv text) This can be more simple than using Google's API for common
+// as .csv text), and converts other office files on upload and export.
+// This can be more simple than using Google's API for common
 // tasks; for anything more complicated use Google's golang sdk directly:
 // https://pkg.go.dev/google.golang.org/api/drive/v3
 package gdrive
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"mime"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 )
 
-// Map google doc type to MIME
-// https://developers.google.com/drive/api/v3/ref-export-formats
-var exportMap = map[string]string{
-	"spreadsheet": "text/csv",
-	"drawing":     "text/svg",
-}
-
 // Define an interface so we can mock the FilesService type for testing if we
 // need to
 type driveFiler interface {
@@ -37,65 +37,88 @@ type driveFiler interface {
 	Get(fileId string) *drive.FilesGetCall
 	Export(fileId string, mimeType string) *drive.FilesExportCall
 	Update(fileId string, file *drive.File) *drive.FilesUpdateCall
+	EmptyTrash() *drive.FilesEmptyTrashCall
+	Copy(fileId string, file *drive.File) *drive.FilesCopyCall
 }
 
-// Service wraps drive.FilesService
+// Service wraps drive.FilesService (and drive.PermissionsService)
 type Service struct {
-	ctx   context.Context
-	filer driveFiler
+	ctx       context.Context
+	filer     driveFiler
+	perms     permissioner
+	client    *http.Client // for requests the drive package does not cover
+	uploadURL string       // replaces the Drive upload endpoint in tests
+
+	// how long to wait before the first retry of a failed upload chunk or
+	// download (default a second); it doubles for each further retry
+	retryPause time.Duration
+
+	// files found by ResolvePath
+	mu    sync.Mutex
+	paths map[string]*drive.File
 }
 
 // NewServiceWithCtx creates and wraps a new FilesService with the provided
 // context
 func NewServiceWithCtx(ctx context.Context) (*Service, error) {
-	gsvc, err := drive.NewService(ctx)
+	client, _, err := htransport.NewClient(ctx, option.WithScopes(drive.DriveScope))
+	if err != nil {
+		return nil, err
+	}
+	gsvc, err := drive.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
 		return nil, err
 	}
 	return &Service{
-		ctx:   ctx,
-		filer: gsvc.Files,
+		ctx:    ctx,
+		filer:  gsvc.Files,
+		perms:  gsvc.Permissions,
+		client: client,
 	}, nil
 }
 
+// httpClient returns the authenticated client used by the Service
+func (svc *Service) httpClient() *http.Client {
+	if svc.client == nil {
+		return http.DefaultClient
+	}
+	return svc.client
+}
+
 // FilesService returns a pointer to the wrapped FilesService
 func (svc *Service) FilesService() *drive.FilesService {
 	return svc.filer.(*drive.FilesService)
 }
 
-// Search searches all of the authenticated user's files.
+// Search searches all of the authenticated user's files and returns every
+// match with the DefaultFields (use SearchEach to choose fields, sort or stop
+// early).
 // 'q' is the search query as documented here:
 // https://developers.google.com/drive/api/v3/ref-search-terms
 func (svc *Service) Search(q string) ([]*drive.File, error) {
-
-	// closure used to iterate pages and collect all files
 	var files []*drive.File
-	pages := func(fl *drive.FileList) error {
-		files = append(files, fl.Files...)
+	err := svc.SearchEach(q, nil, func(f *drive.File) error {
+		files = append(files, f)
 		return nil
-	}
-
-	listCall := svc.filer.List().Fields("files(id, name, parents, shared)").SupportsAllDrives(true).IncludeTeamDriveItems(true).Q(q)
-	err := listCall.Pages(svc.ctx, pages)
-	if err == nil {
-		_, err = listCall.Do()
-	}
+	})
 	return files, err
 }
 
-// FilesNamed returns a list of all files named 'name' in the 'parent' folder.
+// FilesNamed returns a list of all files named 'name' in the 'parent' folder
+// which are not in the trash.
 // If parent is empty, will return files from any files shared with user.
 // If no matching file is found, returns empty list and nil error
 // If an error is encountered it is returned along with any files that were
 // found before encountering the error
 func (svc *Service) FilesNamed(name, parent string) ([]*drive.File, error) {
-	query := fmt.Sprintf("name = '%s'", escapeQuery(name))
+	query := NewQuery().NameIs(name).Trashed(false)
 	if parent != "" {
-		query += fmt.Sprintf(" and '%s' in parents", parent)
+		query.InFolder(parent)
 	}
-	return svc.Search(query)
+	return svc.Search(query.String())
 }
 
+// escapeQuery escapes 'q' for use in a Drive query string literal (see Query)
 func escapeQuery(q string) string {
 	q = strings.ReplaceAll(q, `\`, `\\`)
 	return strings.ReplaceAll(q, `'`, `\'`)
@@ -116,37 +139,139 @@ func (svc *Service) CreateFolder(name, parent string) (*drive.File, error) {
 	return createCall.Do()
 }
 
+// Types of the files which are converted to Google Workspace files when they
+// are uploaded, keyed by extension
+// https://developers.google.com/drive/api/v3/manage-uploads#import_to_google_docs_types_
+var importMap = map[string]string{
+	".csv":  "application/vnd.google-apps.spreadsheet",
+	".tsv":  "application/vnd.google-apps.spreadsheet",
+	".xlsx": "application/vnd.google-apps.spreadsheet",
+	".ods":  "application/vnd.google-apps.spreadsheet",
+	".docx": "application/vnd.google-apps.document",
+	".md":   "application/vnd.google-apps.document",
+}
+
+// Types which Drive can also convert, but are only converted if asked to
+// (see UploadOptions)
+var convertMap = map[string]string{
+	".xls":  "application/vnd.google-apps.spreadsheet",
+	".doc":  "application/vnd.google-apps.document",
+	".odt":  "application/vnd.google-apps.document",
+	".rtf":  "application/vnd.google-apps.document",
+	".txt":  "application/vnd.google-apps.document",
+	".html": "application/vnd.google-apps.document",
+	".pptx": "application/vnd.google-apps.presentation",
+	".ppt":  "application/vnd.google-apps.presentation",
+	".odp":  "application/vnd.google-apps.presentation",
+}
+
 // On Windows the mime.TypeByExtension method can return wrong values
-// (https://github.com/golang/go/issues/32350)
+// (https://github.com/golang/go/issues/32350), and many systems do not know
+// the office types at all.
 // So we hardcode the most important extension(s)
+var extensionTypes = map[string]string{
+	".csv":  "text/csv; charset=utf-8",
+	".tsv":  "text/tab-separated-values; charset=utf-8",
+	".md":   "text/markdown; charset=utf-8",
+	".txt":  "text/plain; charset=utf-8",
+	".xlsx": mimeXLSX,
+	".docx": mimeDOCX,
+	".pptx": mimePPTX,
+	".ods":  mimeODS,
+	".odt":  mimeODT,
+	".odp":  mimeODP,
+}
+
 func typeByExtension(ext string) string {
 	ext = strings.ToLower(ext)
-	if ext == ".csv" {
-		return "text/csv; charset=utf-8"
+	if mime, ok := extensionTypes[ext]; ok {
+		return mime
 	}
 	return mime.TypeByExtension(ext)
 }
 
+// UploadOptions control whether UploadFile converts files to Google
+// Workspace files and how it sends them. By default the types in importMap (.csv, .tsv, .xlsx and
+// .ods to Sheets; .docx and .md to Docs) are converted.
+type UploadOptions struct {
+	// Also convert the other types Drive can import (eg .xls, .txt, .html
+	// and .pptx); it is an error to upload any other type
+	Convert bool
+
+	// Upload the file as it is
+	NoConvert bool
+
+	// Upload the file in chunks of this many bytes (rounded up to a multiple
+	// of 256 KiB; default 8 MiB) with a resumable upload, so that a chunk
+	// which fails can be retried without starting over. Setting ChunkSize or
+	// Progress makes the upload resumable.
+	ChunkSize int64
+
+	// Called after each chunk of a resumable upload with the number of
+	// bytes the server has received and the total size (-1 if the size of
+	// the source cannot be known until it has all been read)
+	Progress func(sent, total int64)
+
+	// How many times in a row to retry a failed chunk, waiting a second
+	// and then twice as long before each further try. 0 disables retries
+	// and a negative value uses the default of 5.
+	Retries int
+}
+
+// convertTo returns the MIME type of the Google Workspace file that a file
+// named 'name' is converted to when uploaded, or "" if it is not converted
+func (opts *UploadOptions) convertTo(name string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	switch {
+	case opts.NoConvert && opts.Convert:
+		return "", errors.New("cannot both convert and not convert")
+	case opts.NoConvert:
+		return "", nil
+	case importMap[ext] != "":
+		return importMap[ext], nil
+	case opts.Convert && convertMap[ext] != "":
+		return convertMap[ext], nil
+	case opts.Convert:
+		return "", fmt.Errorf("Google Drive cannot convert %s files", ext)
+	}
+	return "", nil
+}
+
+// uploadFields are the fields returned for uploaded files
+const uploadFields = "id, name, mimeType, parents, webViewLink"
+
 // CreateFile creats a new file named 'name' in folder with id 'parent' and
 // content read from 'src'.
-// If name has '.csv' extension, then the created file is converted to a Google
-// Sheets document on the drive.
+// If name has an extension in importMap (eg '.csv' or '.xlsx'), then the
+// created file is converted to a Google Workspace document on the drive.
 // If parent is empty, file will be created in user's drive root.
 // If 'src' is nil, creates an empty file.
 // (This will not overwrite any other files with the same name.)
 // https://developers.google.com/drive/api/v3/create-file
 func (svc *Service) CreateFile(name, parent string, src io.Reader) (*drive.File, error) {
-	ext := filepath.Ext(name)
-	mime := typeByExtension(ext)
-	var gmime string
-	if strings.Contains(mime, "text/csv") {
-		gmime = "application/vnd.google-apps.spreadsheet"
+	return svc.createFile(name, parent, src, nil)
+}
+
+// createFile creates a file like CreateFile, converting it as set by 'opts'
+// (which may be nil)
+func (svc *Service) createFile(name, parent string, src io.Reader, opts *UploadOptions) (*drive.File, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
 	}
-	createCall := svc.filer.Create(&drive.File{
+	gmime, err := opts.convertTo(name)
+	if err != nil {
+		return nil, err
+	}
+	meta := &drive.File{
 		Name:     name,
 		MimeType: gmime,
 		Parents:  []string{parent},
-	})
+	}
+	mime := typeByExtension(filepath.Ext(name))
+	if src != nil && opts.resumable() {
+		return svc.uploadResumable("POST", svc.uploadEndpoint(), meta, src, mime, opts)
+	}
+	createCall := svc.filer.Create(meta).SupportsAllDrives(true).Fields(uploadFields)
 	if src != nil {
 		createCall.Media(src, googleapi.ContentType(mime))
 	}
@@ -158,44 +283,119 @@ func (svc *Service) CreateFile(name, parent string, src io.Reader) (*drive.File,
 // of the existing file.
 func (svc *Service) CreateOrUpdateFile(name, parent string,
 	src io.Reader) (*drive.File, error) {
-	var file *drive.File
+	return svc.UploadFile(name, parent, src, nil)
+}
+
+// UploadFile is CreateOrUpdateFile, converting the file as set by 'opts'
+// (which may be nil for the default).
+// The returned file includes its WebViewLink.
+func (svc *Service) UploadFile(name, parent string, src io.Reader, opts *UploadOptions) (*drive.File, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	gmime, err := opts.convertTo(name)
+	if err != nil {
+		return nil, err
+	}
 
 	files, err := svc.FilesNamed(name, parent)
 	if err != nil {
 		return nil, err
 	}
-	if strings.HasSuffix(name, ".csv") &&
-		(files != nil || len(files) == 0) {
+	if gmime != "" && len(files) == 0 {
 		// Try again without the extension
 		// This is because when Google Drive imports .csv files it strips the
 		// ext from the file name, so searching for the same name to update the
 		// file will end up just creating a new file and so on.
-		var name = strings.TrimSuffix(name, ".csv")
-		files, err = svc.FilesNamed(name, parent)
+		// Only match files of the type it would have been converted to, so
+		// that "report.csv" does not replace a PDF named "report".
+		query := NewQuery().NameIs(strings.TrimSuffix(name, filepath.Ext(name))).MimeType(gmime).Trashed(false)
+		if parent != "" {
+			query.InFolder(parent)
+		}
+		files, err = svc.Search(query.String())
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if len(files) > 0 {
-		file, err = svc.UpdateFile(files[0].Id, name, src)
-	} else {
-		file, err = svc.CreateFile(name, parent, src)
+		// keep the existing name (which may have had the ext stripped on import)
+		return svc.updateFile(files[0].Id, files[0].Name, typeByExtension(filepath.Ext(name)), src, opts)
 	}
-	return file, err
+	return svc.createFile(name, parent, src, opts)
 }
 
 // UpdateFile replaces an existing drive file (id) the contents read from 'src'
 // and updates its name to 'name'
 func (svc *Service) UpdateFile(id, name string, src io.Reader) (*drive.File, error) {
-	updateCall := svc.filer.Update(id, &drive.File{})
+	return svc.updateFile(id, name, typeByExtension(filepath.Ext(name)), src, nil)
+}
+
+// updateFile renames the file identified by 'id' to 'name' (unless it is
+// empty) and replaces its contents with 'src' of type 'mime' (unless it is
+// nil), with a resumable upload if 'opts' asks for one
+func (svc *Service) updateFile(id, name, mime string, src io.Reader, opts *UploadOptions) (*drive.File, error) {
+	if src != nil && opts != nil && opts.resumable() {
+		return svc.uploadResumable("PATCH", svc.uploadEndpoint()+"/"+url.PathEscape(id), &drive.File{Name: name}, src, mime, opts)
+	}
+	updateCall := svc.filer.Update(id, &drive.File{Name: name}).SupportsAllDrives(true).Fields(uploadFields)
 	if src != nil {
-		ext := filepath.Ext(name)
-		updateCall.Media(src, googleapi.ContentType(typeByExtension(ext)))
+		updateCall.Media(src, googleapi.ContentType(mime))
 	}
 	return updateCall.Do()
 }
 
+// UpdateMetadata updates the metadata of the file identified by 'id' with the
+// fields set in 'meta' (eg Name, Description or Starred; use ForceSendFields
+// to set a field to its zero value) and returns the updated file
+func (svc *Service) UpdateMetadata(id string, meta *drive.File) (*drive.File, error) {
+	return svc.filer.Update(id, meta).SupportsAllDrives(true).Context(svc.ctx).Do()
+}
+
+// RenameFile renames the file identified by 'id' to 'name'
+func (svc *Service) RenameFile(id, name string) (*drive.File, error) {
+	if name == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+	return svc.UpdateMetadata(id, &drive.File{Name: name})
+}
+
+// MoveFile moves the file identified by 'id' out of its current folder(s)
+// and into the folder identified by 'parent' (which may be in a shared
+// drive)
+func (svc *Service) MoveFile(id, parent string) (*drive.File, error) {
+	if parent == "" {
+		return nil, errors.New("parent cannot be empty")
+	}
+	file, err := svc.filer.Get(id).Fields("parents").SupportsAllDrives(true).Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return svc.filer.Update(id, &drive.File{}).
+		AddParents(parent).
+		RemoveParents(strings.Join(file.Parents, ",")).
+		Fields("id, name, parents").
+		SupportsAllDrives(true).
+		Context(svc.ctx).
+		Do()
+}
+
+// CopyFile copies the file identified by 'id' and returns the copy.
+// The copy is named 'name' and placed in the folder identified by 'parent';
+// if either is empty the original's name or folder is used.
+func (svc *Service) CopyFile(id, name, parent string) (*drive.File, error) {
+	file := &drive.File{Name: name}
+	if parent != "" {
+		file.Parents = []string{parent}
+	}
+	return svc.filer.Copy(id, file).
+		Fields("id, name, parents").
+		SupportsAllDrives(true).
+		Context(svc.ctx).
+		Do()
+}
+
 // GetInfo returns all metadata for the file identified by 'id'
 func (svc *Service) GetInfo(id string) (*drive.File, error) {
 	return svc.filer.Get(id).Fields("*").Do()
@@ -203,43 +403,91 @@ func (svc *Service) GetInfo(id string) (*drive.File, error) {
 
 // DownloadFile returns a http.Response for downloading the contents of file
 // identified by 'id'.
-// If file is a Google Workspace file it is exported as a text format.
+// If file is a Google Workspace file it is exported as a text format (see
+// DownloadFileAs).
 // https://developers.google.com/drive/api/v3/manage-downloads
 func (svc *Service) DownloadFile(id string) (*http.Response, error) {
-	var dlFunc func(...googleapi.CallOption) (*http.Response, error)
-	getCall := svc.filer.Get(id)
+	return svc.DownloadFileAs(id, nil)
+}
+
+// DownloadFileAs returns a http.Response for downloading the contents of the
+// file identified by 'id'. Google Workspace files are exported as the format
+// in 'opts' (which may be nil for the default: csv of the first visible sheet
+// for Sheets docs, svg for Drawings and plain text for anything else); other
+// files are downloaded as they are, and it is an error to ask for a format.
+// https://developers.google.com/drive/api/v3/ref-export-formats
+func (svc *Service) DownloadFileAs(id string, opts *ExportOptions) (*http.Response, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	getCall := svc.filer.Get(id).SupportsAllDrives(true)
 	file, err := getCall.Do()
 	if err != nil {
 		return nil, err
 	}
-	if strings.HasPrefix(file.MimeType, "application/vnd.google-apps") {
-		// it is a google workspace doc we must export
-		parts := strings.Split(file.MimeType, ".")
-		driveType := parts[len(parts)-1]
-		mime := exportMap[driveType]
-		if mime == "" {
-			mime = "text/plain"
-		}
-		dlFunc = svc.filer.Export(id, mime).Download
-	} else {
+	kind := workspaceKind(file.MimeType)
+	if kind == "" {
 		// we can download this file
-		dlFunc = getCall.Download
+		if opts.Format != "" {
+			return nil, fmt.Errorf("%s is not a Google Workspace file, so cannot be exported as %s", file.Name, opts.Format)
+		}
+		return getCall.Download()
 	}
-	return dlFunc()
-}
 
-// FileContents downloads and returns the contents of the file identified by
-// 'id'
-func (svc *Service) FileContents(id string) ([]byte, error) {
-	resp, err := svc.DownloadFile(id)
+	// it is a google workspace doc we must export
+	name, mime, err := exportFormat(kind, opts.Format)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	return ioutil.ReadAll(resp.Body)
+	if opts.Sheet != "" || opts.printing() {
+		if kind != "spreadsheet" {
+			return nil, errors.New("A sheet and print options can only be given for Sheets docs")
+		}
+		return svc.exportSheets(id, name, opts)
+	}
+	return svc.filer.Export(id, mime).Context(svc.ctx).Download()
+}
+
+// FileContents downloads and returns the contents of the file identified by
+// 'id' (use Download or DownloadToFile for large files)
+func (svc *Service) FileContents(id string) ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := svc.Download(id, &buf, nil)
+	return buf.Bytes(), err
 }
 
-// DeleteFile deletes file identified by 'id'
+// DeleteFile permanently deletes file identified by 'id' (skipping the trash)
 func (svc *Service) DeleteFile(id string) error {
 	return svc.filer.Delete(id).Do()
 }
+
+// setTrashed moves the file identified by 'id' into or out of the trash
+func (svc *Service) setTrashed(id string, trashed bool) (*drive.File, error) {
+	return svc.filer.Update(id, &drive.File{
+		Trashed:         trashed,
+		ForceSendFields: []string{"Trashed"},
+	}).SupportsAllDrives(true).Context(svc.ctx).Do()
+}
+
+// TrashFile moves the file identified by 'id' to the trash, from which it can
+// be restored with UntrashFile until the trash is emptied
+func (svc *Service) TrashFile(id string) (*drive.File, error) {
+	return svc.setTrashed(id, true)
+}
+
+// UntrashFile restores the file identified by 'id' from the trash
+func (svc *Service) UntrashFile(id string) (*drive.File, error) {
+	return svc.setTrashed(id, false)
+}
+
+// Trashed returns all of the authenticated user's files which are in the
+// trash
+func (svc *Service) Trashed() ([]*drive.File, error) {
+	return svc.Search(NewQuery().Trashed(true).String())
+}
+
+// EmptyTrash permanently deletes all of the authenticated user's files which
+// are in the trash
+func (svc *Service) EmptyTrash() error {
+	return svc.filer.EmptyTrash().Context(svc.ctx).Do()
+}
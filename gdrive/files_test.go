@@ -0,0 +1,67 @@
+package gdrive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+// newTestService returns a Service which sends all of its requests (Drive
+// API calls, uploads and downloads) to 'h' and waits only briefly before
+// retrying
+func newTestService(t *testing.T, h http.Handler) *Service {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+	gsvc, err := drive.NewService(ctx, option.WithEndpoint(srv.URL+"/"), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Service{
+		ctx:        ctx,
+		filer:      gsvc.Files,
+		client:     srv.Client(),
+		uploadURL:  srv.URL + "/upload/drive/v3/files",
+		retryPause: time.Millisecond,
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	const (
+		sheet = "application/vnd.google-apps.spreadsheet"
+		doc   = "application/vnd.google-apps.document"
+		deck  = "application/vnd.google-apps.presentation"
+	)
+	tests := []struct {
+		name    string
+		opts    UploadOptions
+		want    string
+		wantErr bool
+	}{
+		{"data.csv", UploadOptions{}, sheet, false},
+		{"Data.XLSX", UploadOptions{}, sheet, false},
+		{"notes.md", UploadOptions{}, doc, false},
+		{"notes.txt", UploadOptions{}, "", false},
+		{"image.png", UploadOptions{}, "", false},
+		{"data.csv", UploadOptions{NoConvert: true}, "", false},
+		{"notes.txt", UploadOptions{Convert: true}, doc, false},
+		{"slides.pptx", UploadOptions{Convert: true}, deck, false},
+		{"data.tsv", UploadOptions{Convert: true}, sheet, false},
+		{"image.png", UploadOptions{Convert: true}, "", true},
+		{"data.csv", UploadOptions{Convert: true, NoConvert: true}, "", true},
+	}
+	for _, test := range tests {
+		got, err := test.opts.convertTo(test.name)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s %+v: unexpected error: %v", test.name, test.opts, err)
+		}
+		if got != test.want {
+			t.Errorf("%s %+v: got %q, want %q", test.name, test.opts, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package gdrive
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultFields are requested for each file when ListOptions.Fields is empty
+var DefaultFields = []string{"id", "name", "parents", "shared"}
+
+// LongFields are the fields needed for a long listing (see 'gsheet list -l')
+var LongFields = []string{"id", "name", "parents", "shared", "mimeType", "size", "modifiedTime", "owners(displayName, emailAddress)"}
+
+// StopSearch can be returned by the function passed to SearchEach or Walk to
+// stop early without an error
+var StopSearch = errors.New("stop search")
+
+// ListOptions control which files SearchEach and Walk return and which of
+// their fields are fetched
+type ListOptions struct {
+	// File fields to request (eg "id", "size", "owners(emailAddress)"); see
+	// https://developers.google.com/drive/api/v3/reference/files
+	// Defaults to DefaultFields.
+	Fields []string
+
+	// Sort keys as documented for files.list (eg "folder,name" or
+	// "modifiedTime desc")
+	OrderBy string
+
+	// Stop after this many files (0 for no limit)
+	Max int
+}
+
+// fields returns the fields to request for each file, adding any of 'needed'
+// which are missing
+func (opts *ListOptions) fields(needed ...string) string {
+	fields := DefaultFields
+	if opts != nil && len(opts.Fields) > 0 {
+		fields = opts.Fields
+	}
+	have := make(map[string]bool)
+	for _, f := range fields {
+		have[f] = true
+	}
+	for _, f := range needed {
+		if !have[f] {
+			fields = append(fields[:len(fields):len(fields)], f)
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// SearchEach calls 'fn' with each of the authenticated user's files matching
+// the query 'q' (see Search). Pages of results are only requested as 'fn'
+// consumes them, so returning StopSearch from 'fn' (or reaching opts.Max)
+// avoids fetching the rest. 'opts' may be nil.
+func (svc *Service) SearchEach(q string, opts *ListOptions, fn func(*drive.File) error) error {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+	seen := 0
+	_, err := svc.searchEach(q, opts, opts.fields(), &seen, fn)
+	return err
+}
+
+// searchEach does the work of SearchEach, counting the files passed to 'fn'
+// in 'seen' so that Walk can apply opts.Max across folders. It returns
+// whether the caller should stop.
+func (svc *Service) searchEach(q string, opts *ListOptions, fields string, seen *int, fn func(*drive.File) error) (bool, error) {
+	call := svc.filer.List().
+		Fields("nextPageToken", googleapi.Field("files("+fields+")")).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true).
+		Q(q)
+	if opts.OrderBy != "" {
+		call.OrderBy(opts.OrderBy)
+	}
+	token := ""
+	for {
+		if opts.Max > 0 {
+			// no point fetching more than we will use
+			size := int64(opts.Max - *seen)
+			if size > 1000 {
+				size = 1000
+			}
+			call.PageSize(size)
+		}
+		fl, err := call.PageToken(token).Context(svc.ctx).Do()
+		if err != nil {
+			return true, err
+		}
+		for _, f := range fl.Files {
+			if err := fn(f); err != nil {
+				if err == StopSearch {
+					err = nil
+				}
+				return true, err
+			}
+			*seen++
+			if opts.Max > 0 && *seen >= opts.Max {
+				return true, nil
+			}
+		}
+		if fl.NextPageToken == "" {
+			return false, nil
+		}
+		token = fl.NextPageToken
+	}
+}
+
+// Walk calls 'fn' with every file which is not trashed in the folder
+// identified by 'folder' and, recursively, in its sub-folders. 'fn' also gets
+// the slash separated path of the file relative to 'folder'.
+// A folder is passed to 'fn' before its contents. opts.OrderBy applies within
+// each folder and opts.Max to the total number of files. Return StopSearch
+// from 'fn' to stop early. 'opts' may be nil.
+func (svc *Service) Walk(folder string, opts *ListOptions, fn func(p string, f *drive.File) error) error {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+	seen := 0
+	_, err := svc.walk(folder, "", opts, opts.fields("id", "name", "mimeType"), &seen, fn)
+	return err
+}
+
+func (svc *Service) walk(folder, dir string, opts *ListOptions, fields string, seen *int, fn func(string, *drive.File) error) (bool, error) {
+	q := NewQuery().InFolder(folder).Trashed(false).String()
+	var folders []*drive.File
+	var paths []string
+	stop, err := svc.searchEach(q, opts, fields, seen, func(f *drive.File) error {
+		p := path.Join(dir, f.Name)
+		if err := fn(p, f); err != nil {
+			return err
+		}
+		if f.MimeType == folderMime {
+			folders = append(folders, f)
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if stop || err != nil {
+		return stop, err
+	}
+	for i, f := range folders {
+		if stop, err := svc.walk(f.Id, paths[i], opts, fields, seen, fn); stop || err != nil {
+			return stop, err
+		}
+	}
+	return false, nil
+}
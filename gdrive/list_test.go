@@ -0,0 +1,150 @@
+package gdrive
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+var (
+	nameTerm   = regexp.MustCompile(`name = '([^']*)'`)
+	parentTerm = regexp.MustCompile(`'([^']*)' in parents`)
+	mimeTerm   = regexp.MustCompile(`mimeType = '([^']*)'`)
+)
+
+// fakeDrive is a stand-in for Drive's files.list which understands the name,
+// parent and MIME type terms of a query and returns at most pageSize files
+// per page
+type fakeDrive struct {
+	mu       sync.Mutex
+	files    []*drive.File // in the order they are listed
+	pageSize int
+	lists    int // number of files.list requests
+}
+
+func (f *fakeDrive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path != "/files" {
+		http.NotFound(w, r)
+		return
+	}
+	f.lists++
+	q := r.URL.Query().Get("q")
+	var matches []*drive.File
+	for _, file := range f.files {
+		if m := nameTerm.FindStringSubmatch(q); m != nil && file.Name != m[1] {
+			continue
+		}
+		if m := parentTerm.FindStringSubmatch(q); m != nil && file.Parents[0] != m[1] {
+			continue
+		}
+		if m := mimeTerm.FindStringSubmatch(q); m != nil && file.MimeType != m[1] {
+			continue
+		}
+		matches = append(matches, file)
+	}
+
+	size := f.pageSize
+	if s, _ := strconv.Atoi(r.URL.Query().Get("pageSize")); s > 0 && s < size {
+		size = s
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := start + size
+	list := &drive.FileList{}
+	if end < len(matches) {
+		list.NextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(matches)
+	}
+	list.Files = matches[start:end]
+	json.NewEncoder(w).Encode(list)
+}
+
+// add adds a file (or a folder, if 'folder' is set) to the fake
+func (f *fakeDrive) add(id, name, parent string, folder bool) {
+	file := &drive.File{Id: id, Name: name, Parents: []string{parent}, MimeType: "text/plain"}
+	if folder {
+		file.MimeType = folderMime
+	}
+	f.files = append(f.files, file)
+}
+
+func TestSearchEach(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		stopAfter int // return StopSearch after this many files
+		want      []string
+		wantLists int
+	}{
+		{"all", 0, 0, []string{"a", "b", "c", "d", "e"}, 3},
+		{"max across pages", 3, 0, []string{"a", "b", "c"}, 2},
+		{"max of one page", 2, 0, []string{"a", "b"}, 1},
+		{"stop", 0, 1, []string{"a"}, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &fakeDrive{pageSize: 2}
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				fake.add(name, name, "root", false)
+			}
+			svc := newTestService(t, fake)
+			var got []string
+			err := svc.SearchEach(NewQuery().InFolder("root").String(), &ListOptions{Max: test.max}, func(f *drive.File) error {
+				got = append(got, f.Name)
+				if len(got) == test.stopAfter {
+					return StopSearch
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if fake.lists != test.wantLists {
+				t.Errorf("made %d requests, want %d", fake.lists, test.wantLists)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		max  int
+		want []string
+	}{
+		// each folder is listed before walking its sub-folders
+		{0, []string{"a", "b", "a/c", "a/e", "a/c/d"}},
+		{3, []string{"a", "b", "a/c"}},
+		{4, []string{"a", "b", "a/c", "a/e"}},
+	}
+	for _, test := range tests {
+		fake := &fakeDrive{pageSize: 2}
+		fake.add("a", "a", "root", true)
+		fake.add("b", "b", "root", false)
+		fake.add("c", "c", "a", true)
+		fake.add("e", "e", "a", false)
+		fake.add("d", "d", "c", false)
+		svc := newTestService(t, fake)
+		var got []string
+		err := svc.Walk("root", &ListOptions{Max: test.max}, func(p string, f *drive.File) error {
+			got = append(got, p)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("max %d: got %v, want %v", test.max, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package gdrive
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+)
+
+const folderMime = "application/vnd.google-apps.folder"
+
+// splitPath splits a slash separated path into its names, ignoring empty
+// names (so "/Reports//2024/" is "Reports", "2024")
+func splitPath(p string) []string {
+	var names []string
+	for _, name := range strings.Split(p, "/") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// lookup returns the files (or only folders if 'folder' is set) named 'name'
+// which are not trashed in the folder identified by 'parent'
+func (svc *Service) lookup(parent, name string, folder bool) ([]*drive.File, error) {
+	q := NewQuery().NameIs(name).InFolder(parent).Trashed(false)
+	if folder {
+		q.MimeType(folderMime)
+	}
+	return svc.Search(q.String())
+}
+
+// cached returns the file previously resolved for 'key'
+func (svc *Service) cached(key string) *drive.File {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	return svc.paths[key]
+}
+
+// cache remembers that 'key' resolved to 'file'
+func (svc *Service) cache(key string, file *drive.File) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.paths == nil {
+		svc.paths = make(map[string]*drive.File)
+	}
+	svc.paths[key] = file
+}
+
+// ResolvePath returns the file at the slash separated 'path' (eg
+// "/Reports/2024/Q1/sales"), starting from the root of the user's drive.
+// Every name but the last must be a folder. Trashed files are ignored.
+// Since Drive allows several files with the same name in a folder, it is an
+// error if any name on the path matches more than one file.
+// Resolved paths are cached for the life of the Service.
+func (svc *Service) ResolvePath(path string) (*drive.File, error) {
+	names := splitPath(path)
+	if len(names) == 0 {
+		return &drive.File{Id: "root", Name: "/", MimeType: folderMime}, nil
+	}
+
+	parent := "root"
+	var file *drive.File
+	for i, name := range names {
+		dir := "/" + strings.Join(names[:i], "/")
+		key := dir + "\x00" + name
+		if file = svc.cached(key); file != nil {
+			parent = file.Id
+			continue
+		}
+		files, err := svc.lookup(parent, name, i < len(names)-1)
+		if err != nil {
+			return nil, err
+		}
+		switch len(files) {
+		case 0:
+			return nil, fmt.Errorf("No file named %s found in %s", name, dir)
+		case 1:
+			file = files[0]
+		default:
+			var ids []string
+			for _, f := range files {
+				ids = append(ids, f.Id)
+			}
+			return nil, fmt.Errorf("%d files named %s found in %s (%s); use an id instead",
+				len(files), name, dir, strings.Join(ids, ", "))
+		}
+		svc.cache(key, file)
+		parent = file.Id
+	}
+	return file, nil
+}
+
+// CreateFolders creates the folders named by the slash separated 'path' in
+// the folder identified by 'parent' (or the root of the user's drive if the
+// path starts with a slash or 'parent' is empty), skipping any which already
+// exist, like 'mkdir -p'. It returns the last folder.
+func (svc *Service) CreateFolders(path, parent string) (*drive.File, error) {
+	names := splitPath(path)
+	if len(names) == 0 {
+		return nil, errors.New("path cannot be empty")
+	}
+	if parent == "" || strings.HasPrefix(path, "/") {
+		parent = "root"
+	}
+	var folder *drive.File
+	for _, name := range names {
+		folders, err := svc.lookup(parent, name, true)
+		if err != nil {
+			return nil, err
+		}
+		switch len(folders) {
+		case 0:
+			folder, err = svc.CreateFolder(name, parent)
+			if err != nil {
+				return nil, err
+			}
+		case 1:
+			folder = folders[0]
+		default:
+			return nil, fmt.Errorf("%d folders named %s found in %s", len(folders), name, parent)
+		}
+		parent = folder.Id
+	}
+	return folder, nil
+}
@@ -0,0 +1,74 @@
+package gdrive
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	fake := &fakeDrive{pageSize: 100}
+	fake.add("reports", "Reports", "root", true)
+	fake.add("2024", "2024", "reports", true)
+	fake.add("sales", "sales", "2024", false)
+	fake.add("dup1", "dup", "root", true)
+	fake.add("dup2", "dup", "root", true)
+	// a file named like a folder on the path is not a folder
+	fake.add("notes", "notes", "reports", false)
+	svc := newTestService(t, fake)
+
+	tests := []struct {
+		path    string
+		wantId  string
+		wantErr string
+	}{
+		{"/", "root", ""},
+		{"/Reports/2024/sales", "sales", ""},
+		{"Reports//2024/", "2024", ""},
+		{"/Reports/2023/sales", "", "No file named 2023 found in /Reports"},
+		{"/Reports/notes/x", "", "No file named notes found in /Reports"},
+		{"/dup/x", "", "2 files named dup found in / (dup1, dup2)"},
+	}
+	for _, test := range tests {
+		file, err := svc.ResolvePath(test.path)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: got error %v, want %q", test.path, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.path, err)
+			continue
+		}
+		if file.Id != test.wantId {
+			t.Errorf("%s: got file %s, want %s", test.path, file.Id, test.wantId)
+		}
+	}
+}
+
+func TestResolvePathCache(t *testing.T) {
+	fake := &fakeDrive{pageSize: 100}
+	fake.add("reports", "Reports", "root", true)
+	fake.add("2024", "2024", "reports", true)
+	fake.add("sales", "sales", "2024", false)
+	svc := newTestService(t, fake)
+
+	if _, err := svc.ResolvePath("/Reports/2024/sales"); err != nil {
+		t.Fatal(err)
+	}
+	if fake.lists != 3 {
+		t.Errorf("made %d requests, want one per name", fake.lists)
+	}
+	for _, p := range []string{"/Reports/2024/sales", "Reports/2024"} {
+		file, err := svc.ResolvePath(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fake.lists != 3 {
+			t.Errorf("%s: made %d more requests, want none", p, fake.lists-3)
+		}
+		if file.Id != "sales" && file.Id != "2024" {
+			t.Errorf("%s: got file %s", p, file.Id)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package gdrive
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// Define an interface so we can mock the PermissionsService type for testing
+// if we need to
+type permissioner interface {
+	Create(fileId string, permission *drive.Permission) *drive.PermissionsCreateCall
+	Delete(fileId, permissionId string) *drive.PermissionsDeleteCall
+	List(fileId string) *drive.PermissionsListCall
+	Update(fileId, permissionId string, permission *drive.Permission) *drive.PermissionsUpdateCall
+}
+
+// permissionFields are the fields returned for each permission
+const permissionFields = "id,type,role,emailAddress,domain,displayName,allowFileDiscovery,pendingOwner,deleted,permissionDetails(inherited,inheritedFrom)"
+
+// Roles which can be granted by a permission.
+// Organizer and FileOrganizer only apply to shared drives.
+const (
+	RoleReader        = "reader"
+	RoleCommenter     = "commenter"
+	RoleWriter        = "writer"
+	RoleFileOrganizer = "fileOrganizer"
+	RoleOrganizer     = "organizer"
+	RoleOwner         = "owner"
+)
+
+// User returns a permission granting 'role' to the user with 'email'
+func User(email, role string) *drive.Permission {
+	return &drive.Permission{Type: "user", EmailAddress: email, Role: role}
+}
+
+// Group returns a permission granting 'role' to the Google group with 'email'
+func Group(email, role string) *drive.Permission {
+	return &drive.Permission{Type: "group", EmailAddress: email, Role: role}
+}
+
+// Domain returns a permission granting 'role' to everybody in 'domain'.
+// If 'discoverable' is set they can find the file by searching; otherwise
+// they need the link.
+func Domain(domain, role string, discoverable bool) *drive.Permission {
+	return &drive.Permission{Type: "domain", Domain: domain, Role: role, AllowFileDiscovery: discoverable}
+}
+
+// Anyone returns a permission granting 'role' to anybody (see Domain for
+// 'discoverable')
+func Anyone(role string, discoverable bool) *drive.Permission {
+	return &drive.Permission{Type: "anyone", Role: role, AllowFileDiscovery: discoverable}
+}
+
+// Share adds the permission 'p' (see User, Group, Domain and Anyone) to the
+// file identified by 'id' and returns the new permission.
+// If 'notify' is set, users and groups are sent an email about the share
+// containing 'message'. Files in shared drives are supported.
+// Use TransferOwnership rather than sharing with RoleOwner.
+func (svc *Service) Share(id string, p *drive.Permission, notify bool, message string) (*drive.Permission, error) {
+	if p.Role == RoleOwner {
+		return nil, errors.New("use TransferOwnership to change the owner of a file")
+	}
+	call := svc.perms.Create(id, p).
+		SupportsAllDrives(true).
+		Fields(permissionFields).
+		Context(svc.ctx)
+	if p.Type == "user" || p.Type == "group" {
+		// notification emails can only be sent to users and groups
+		call.SendNotificationEmail(notify)
+		if notify && message != "" {
+			call.EmailMessage(message)
+		}
+	}
+	return call.Do()
+}
+
+// Permissions returns all of the permissions on the file identified by 'id'
+func (svc *Service) Permissions(id string) ([]*drive.Permission, error) {
+	var perms []*drive.Permission
+	err := svc.perms.List(id).
+		SupportsAllDrives(true).
+		Fields("nextPageToken", "permissions("+permissionFields+")").
+		Pages(svc.ctx, func(pl *drive.PermissionList) error {
+			perms = append(perms, pl.Permissions...)
+			return nil
+		})
+	return perms, err
+}
+
+// FindPermission returns the permission on the file identified by 'id' which
+// matches 'who': a permission id, an email address, a domain or "anyone"
+func (svc *Service) FindPermission(id, who string) (*drive.Permission, error) {
+	perms, err := svc.Permissions(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range perms {
+		switch {
+		case p.Id == who,
+			p.EmailAddress != "" && strings.EqualFold(p.EmailAddress, who),
+			p.Type == "domain" && strings.EqualFold(p.Domain, who),
+			p.Type == "anyone" && who == "anyone":
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("No permission for %s found on file %s", who, id)
+}
+
+// UpdatePermission changes the role of the permission identified by
+// 'permissionId' on the file identified by 'id' to 'role'
+func (svc *Service) UpdatePermission(id, permissionId, role string) (*drive.Permission, error) {
+	if role == RoleOwner {
+		return nil, errors.New("use TransferOwnership to change the owner of a file")
+	}
+	return svc.perms.Update(id, permissionId, &drive.Permission{Role: role}).
+		SupportsAllDrives(true).
+		Fields(permissionFields).
+		Context(svc.ctx).
+		Do()
+}
+
+// RevokePermission removes the permission identified by 'permissionId' from
+// the file identified by 'id'
+func (svc *Service) RevokePermission(id, permissionId string) error {
+	return svc.perms.Delete(id, permissionId).
+		SupportsAllDrives(true).
+		Context(svc.ctx).
+		Do()
+}
+
+// TransferOwnership makes the user with 'email' the owner of the file
+// identified by 'id'. The current owner keeps write access.
+// Files in shared drives are owned by the drive and cannot be transferred.
+func (svc *Service) TransferOwnership(id, email string) (*drive.Permission, error) {
+	perms, err := svc.Permissions(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range perms {
+		if p.Type == "user" && strings.EqualFold(p.EmailAddress, email) {
+			return svc.perms.Update(id, p.Id, &drive.Permission{Role: RoleOwner}).
+				TransferOwnership(true).
+				Fields(permissionFields).
+				Context(svc.ctx).
+				Do()
+		}
+	}
+	return svc.perms.Create(id, User(email, RoleOwner)).
+		TransferOwnership(true).
+		Fields(permissionFields).
+		Context(svc.ctx).
+		Do()
+}
@@ -0,0 +1,123 @@
+package gdrive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query builds a Drive search query (for Search, SearchEach or 'gsheet
+// search') from filters which are all required to match. Values are escaped,
+// so they may contain quotes and backslashes. For example:
+//
+//	q := NewQuery().Kind("spreadsheet").NameContains("budget").Trashed(false).String()
+//
+// See https://developers.google.com/drive/api/v3/ref-search-terms
+type Query struct {
+	terms []string
+}
+
+// NewQuery returns an empty query, which matches every file
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// quote returns 's' as a Drive query string literal
+func quote(s string) string {
+	return "'" + escapeQuery(s) + "'"
+}
+
+// add appends a term to the query
+func (q *Query) add(format string, args ...interface{}) *Query {
+	q.terms = append(q.terms, fmt.Sprintf(format, args...))
+	return q
+}
+
+// NameIs matches files named exactly 'name'
+func (q *Query) NameIs(name string) *Query {
+	return q.add("name = %s", quote(name))
+}
+
+// NameContains matches files whose name contains 's'. (Drive matches the
+// start of words, so "port" matches "port.csv" but not "report.csv".)
+func (q *Query) NameContains(s string) *Query {
+	return q.add("name contains %s", quote(s))
+}
+
+// FullText matches files whose name, description or content contains 's'
+func (q *Query) FullText(s string) *Query {
+	return q.add("fullText contains %s", quote(s))
+}
+
+// MimeType matches files with the MIME type 'mime'
+func (q *Query) MimeType(mime string) *Query {
+	return q.add("mimeType = %s", quote(mime))
+}
+
+// Kind matches Google files of the kind 'kind' (spreadsheet, document,
+// presentation, folder, drawing, form, ...), ie with the MIME type
+// "application/vnd.google-apps.<kind>". A 'kind' containing a slash is taken
+// to be a MIME type.
+func (q *Query) Kind(kind string) *Query {
+	if strings.Contains(kind, "/") {
+		return q.MimeType(kind)
+	}
+	return q.MimeType("application/vnd.google-apps." + strings.ToLower(kind))
+}
+
+// InFolder matches files in the folder identified by 'parent' (which may be
+// "root")
+func (q *Query) InFolder(parent string) *Query {
+	return q.add("%s in parents", quote(parent))
+}
+
+// ModifiedAfter matches files last modified after 't'
+func (q *Query) ModifiedAfter(t time.Time) *Query {
+	return q.add("modifiedTime > %s", quote(t.UTC().Format(time.RFC3339)))
+}
+
+// ModifiedBefore matches files last modified before 't'
+func (q *Query) ModifiedBefore(t time.Time) *Query {
+	return q.add("modifiedTime < %s", quote(t.UTC().Format(time.RFC3339)))
+}
+
+// Owner matches files owned by the user with 'email'
+func (q *Query) Owner(email string) *Query {
+	return q.add("%s in owners", quote(email))
+}
+
+// SharedWithMe matches files in the authenticated user's "Shared with me"
+// collection
+func (q *Query) SharedWithMe() *Query {
+	return q.add("sharedWithMe = true")
+}
+
+// Trashed matches files which are (or, if 'trashed' is false, are not) in
+// the trash
+func (q *Query) Trashed(trashed bool) *Query {
+	return q.add("trashed = %t", trashed)
+}
+
+// Starred matches files which are (or are not) starred
+func (q *Query) Starred(starred bool) *Query {
+	return q.add("starred = %t", starred)
+}
+
+// AppProperty matches files with the custom app property 'key' set to 'value'
+func (q *Query) AppProperty(key, value string) *Query {
+	return q.add("appProperties has { key=%s and value=%s }", quote(key), quote(value))
+}
+
+// Raw adds a term written in the Drive query language as is. It is wrapped
+// in parentheses so that any 'or' in it does not affect the other terms.
+func (q *Query) Raw(term string) *Query {
+	if term == "" {
+		return q
+	}
+	return q.add("(%s)", term)
+}
+
+// String returns the query in the Drive query language
+func (q *Query) String() string {
+	return strings.Join(q.terms, " and ")
+}
@@ -0,0 +1,33 @@
+package gdrive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	when := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		q    *Query
+		want string
+	}{
+		{NewQuery(), ""},
+		{NewQuery().NameIs("budget"), "name = 'budget'"},
+		{NewQuery().NameIs(`Bob's \ sheet`), `name = 'Bob\'s \\ sheet'`},
+		{NewQuery().InFolder("it's").Trashed(false), `'it\'s' in parents and trashed = false`},
+		{NewQuery().Kind("spreadsheet"), "mimeType = 'application/vnd.google-apps.spreadsheet'"},
+		{NewQuery().Kind("text/csv"), "mimeType = 'text/csv'"},
+		{NewQuery().ModifiedAfter(when).ModifiedBefore(when.Add(time.Hour)),
+			"modifiedTime > '2024-03-01T09:30:00Z' and modifiedTime < '2024-03-01T10:30:00Z'"},
+		{NewQuery().Owner("a@example.com").SharedWithMe().Starred(true),
+			"'a@example.com' in owners and sharedWithMe = true and starred = true"},
+		{NewQuery().AppProperty("team", "o'brien"), `appProperties has { key='team' and value='o\'brien' }`},
+		{NewQuery().NameContains("q1").Raw("starred or shared"), "name contains 'q1' and (starred or shared)"},
+		{NewQuery().Raw(""), ""},
+	}
+	for _, test := range tests {
+		if got := test.q.String(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
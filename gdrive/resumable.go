@@ -0,0 +1,301 @@
+package gdrive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// Resumable uploads
+// https://developers.google.com/drive/api/guides/manage-uploads#resumable
+
+const (
+	uploadURL = "https://www.googleapis.com/upload/drive/v3/files"
+
+	// chunks must be a multiple of this size (except for the last)
+	minChunkSize     = 256 * 1024
+	defaultChunkSize = 8 * 1024 * 1024
+	defaultRetries   = 5
+)
+
+// resumable reports whether files should be uploaded with a resumable upload
+func (opts *UploadOptions) resumable() bool {
+	return opts.ChunkSize > 0 || opts.Progress != nil
+}
+
+// chunkSize returns opts.ChunkSize rounded up to a multiple of minChunkSize,
+// or defaultChunkSize if it is not set
+func (opts *UploadOptions) chunkSize() int64 {
+	if opts.ChunkSize <= 0 {
+		return defaultChunkSize
+	}
+	return (opts.ChunkSize + minChunkSize - 1) / minChunkSize * minChunkSize
+}
+
+// sourceSize returns the size of 'src' if it can tell (files, bytes.Reader,
+// strings.Reader, ...) or -1
+func sourceSize(src io.Reader) int64 {
+	switch s := src.(type) {
+	case interface{ Size() int64 }:
+		return s.Size()
+	case interface{ Stat() (os.FileInfo, error) }:
+		if info, err := s.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return -1
+}
+
+// uploadEndpoint returns the URL files are uploaded to
+func (svc *Service) uploadEndpoint() string {
+	if svc.uploadURL != "" {
+		return svc.uploadURL
+	}
+	return uploadURL
+}
+
+// resumableUpload is an upload session which sends the contents of 'src' in
+// chunks. Only the current chunk is kept in memory, so any reader can be
+// uploaded and still resumed from the last byte the server acknowledged.
+type resumableUpload struct {
+	svc     *Service
+	session string // URI of the upload session
+	src     *bufio.Reader
+	total   int64 // size of the upload, or -1 until we reach the end of 'src'
+	opts    *UploadOptions
+}
+
+// uploadResumable uploads the contents of 'src' of type 'mime' with the
+// metadata 'meta' using a resumable upload session, created with a 'method'
+// request to 'url' (POST to create a file or PATCH to update one).
+// Failed chunks are retried opts.Retries times, resuming from the last byte
+// the server received.
+func (svc *Service) uploadResumable(method, url string, meta *drive.File, src io.Reader, mime string, opts *UploadOptions) (*drive.File, error) {
+	u := &resumableUpload{
+		svc:   svc,
+		src:   bufio.NewReader(src),
+		total: sourceSize(src),
+		opts:  opts,
+	}
+	var err error
+	u.session, err = svc.startUpload(method, url, meta, mime, u.total)
+	if err != nil {
+		return nil, err
+	}
+	return u.run()
+}
+
+// startUpload starts an upload session and returns its URI
+func (svc *Service) startUpload(method, uri string, meta *drive.File, mime string, size int64) (string, error) {
+	body, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"uploadType":        {"resumable"},
+		"supportsAllDrives": {"true"},
+		"fields":            {uploadFields},
+	}
+	req, err := http.NewRequestWithContext(svc.ctx, method, uri+"?"+params.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	if mime != "" {
+		req.Header.Set("X-Upload-Content-Type", mime)
+	}
+	if size >= 0 {
+		req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	}
+	resp, err := svc.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return "", err
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", errors.New("no upload session in response")
+	}
+	return session, nil
+}
+
+// run sends the chunks of the upload and returns the uploaded file
+func (u *resumableUpload) run() (*drive.File, error) {
+	retries := u.opts.Retries
+	if retries < 0 {
+		retries = defaultRetries
+	}
+	buf := make([]byte, u.opts.chunkSize())
+	var chunk []byte
+	var start, sent int64 // offset of chunk and bytes acknowledged
+	final := false
+	// failed requests since the server last acknowledged more data
+	failures := 0
+	// after a failure, ask how much the server got before sending more
+	query := false
+	for {
+		if !query && sent >= start+int64(len(chunk)) && !final {
+			// the whole chunk was received; read the next
+			start = sent
+			n, err := io.ReadFull(u.src, buf)
+			switch {
+			case err == io.EOF || err == io.ErrUnexpectedEOF:
+				final = true
+			case err != nil:
+				return nil, err
+			default:
+				_, err = u.src.Peek(1)
+				final = err == io.EOF
+			}
+			chunk = buf[:n]
+			if final {
+				u.total = start + int64(n)
+			}
+		}
+
+		var data []byte // nil for a status query
+		if !query {
+			data = chunk[sent-start:]
+		}
+		file, next, err := u.send(data, sent, final)
+		if err == nil && file == nil && next == sent && len(data) > 0 {
+			err = retryableError{errors.New("upload server did not accept any data")}
+		}
+		if err != nil {
+			if !isRetryable(err) || failures >= retries {
+				return nil, err
+			}
+			if err := u.svc.wait(failures); err != nil {
+				return nil, err
+			}
+			failures++
+			query = true
+			continue
+		}
+		query = false
+		if next < sent || next > start+int64(len(chunk)) {
+			return nil, fmt.Errorf("upload server acknowledged byte %d, outside of the chunk sent", next)
+		}
+		if next > sent {
+			failures = 0
+			if u.opts.Progress != nil {
+				u.opts.Progress(next, u.total)
+			}
+		}
+		sent = next
+		if file != nil {
+			return file, nil
+		}
+	}
+}
+
+// wait pauses before retry number 'n' (counting from 0) of a failed request,
+// doubling the pause for each retry. It returns early with an error if the
+// Service's context is done.
+func (svc *Service) wait(n int) error {
+	pause := svc.retryPause
+	if pause == 0 {
+		pause = time.Second
+	}
+	timer := time.NewTimer(pause << n)
+	defer timer.Stop()
+	select {
+	case <-svc.ctx.Done():
+		return svc.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryableError is an error after which an upload can be resumed
+type retryableError struct {
+	error
+}
+
+func isRetryable(err error) bool {
+	var re retryableError
+	return errors.As(err, &re)
+}
+
+// send PUTs 'data', which starts at byte 'off' of the upload, to the upload
+// session. If 'final' is set it is the end of the upload. A nil 'data' only
+// asks for the status of the session.
+// It returns the uploaded file once the upload is complete and the offset of
+// the first byte the server has not yet received.
+func (u *resumableUpload) send(data []byte, off int64, final bool) (*drive.File, int64, error) {
+	total := "*"
+	if u.total >= 0 {
+		total = strconv.FormatInt(u.total, 10)
+	}
+	var contentRange string
+	switch {
+	case data == nil:
+		contentRange = "bytes */" + total
+	case len(data) == 0:
+		contentRange = fmt.Sprintf("bytes */%d", off)
+	default:
+		if final {
+			total = strconv.FormatInt(off+int64(len(data)), 10)
+		}
+		contentRange = fmt.Sprintf("bytes %d-%d/%s", off, off+int64(len(data))-1, total)
+	}
+
+	req, err := http.NewRequestWithContext(u.svc.ctx, "PUT", u.session, bytes.NewReader(data))
+	if err != nil {
+		return nil, off, err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Range", contentRange)
+	resp, err := u.svc.httpClient().Do(req)
+	if err != nil {
+		if u.svc.ctx.Err() != nil {
+			return nil, off, err
+		}
+		return nil, off, retryableError{err}
+	}
+	defer resp.Body.Close()
+
+	switch code := resp.StatusCode; {
+	case code == http.StatusOK || code == http.StatusCreated:
+		file := &drive.File{}
+		if err := json.NewDecoder(resp.Body).Decode(file); err != nil {
+			return nil, off, err
+		}
+		if u.total >= 0 {
+			off = u.total
+		}
+		return file, off, nil
+	case code == http.StatusPermanentRedirect:
+		// incomplete; Range is the bytes received so far (if any)
+		r := strings.TrimPrefix(resp.Header.Get("Range"), "bytes=")
+		if r == "" {
+			return nil, 0, nil
+		}
+		_, last, _ := strings.Cut(r, "-")
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil {
+			return nil, off, fmt.Errorf("bad Range from upload server: %s", r)
+		}
+		return nil, n + 1, nil
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return nil, off, errors.New("the upload session expired; start the upload again")
+	case code >= 500 || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout:
+		return nil, off, retryableError{googleapi.CheckResponse(resp)}
+	}
+	return nil, off, googleapi.CheckResponse(resp)
+}
@@ -0,0 +1,239 @@
+package gdrive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// fakeUploads is a stand-in for the Drive upload server which speaks the
+// resumable upload protocol. It can fail chunks (after keeping part of them)
+// to test resuming.
+type fakeUploads struct {
+	t  *testing.T
+	mu sync.Mutex
+
+	meta     drive.File // metadata of the upload
+	method   string     // method which started the session
+	path     string     // path which started the session
+	received []byte
+	total    int64 // -1 until known
+	done     bool
+	puts     int
+
+	// fail these PUTs (numbered from 1, including status queries) of data
+	// with a 503 after keeping half of the data
+	failPuts map[int]bool
+
+	// fail every PUT with a 503
+	down bool
+
+	// fail every PUT of data with a 503, keeping none of it, but answer
+	// status queries
+	failData bool
+}
+
+func (f *fakeUploads) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path != "/session" {
+		if r.URL.Query().Get("uploadType") != "resumable" {
+			f.t.Errorf("uploadType = %q", r.URL.Query().Get("uploadType"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&f.meta); err != nil {
+			f.t.Error(err)
+		}
+		f.method, f.path = r.Method, r.URL.Path
+		f.total = -1
+		if size := r.Header.Get("X-Upload-Content-Length"); size != "" {
+			f.total, _ = strconv.ParseInt(size, 10, 64)
+		}
+		w.Header().Set("Location", "http://"+r.Host+"/session")
+		return
+	}
+
+	if r.Method != "PUT" {
+		f.t.Errorf("%s to upload session", r.Method)
+	}
+	f.puts++
+	if f.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		f.t.Error(err)
+	}
+	// Content-Range is "bytes FIRST-LAST/TOTAL" or "bytes */TOTAL"
+	rng, total, _ := strings.Cut(strings.TrimPrefix(r.Header.Get("Content-Range"), "bytes "), "/")
+	if total != "*" {
+		f.total, _ = strconv.ParseInt(total, 10, 64)
+	}
+	if rng != "*" {
+		first, _, _ := strings.Cut(rng, "-")
+		off, _ := strconv.ParseInt(first, 10, 64)
+		if off != int64(len(f.received)) {
+			f.t.Errorf("chunk starts at %d, but have %d bytes", off, len(f.received))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if f.failData {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if f.failPuts[f.puts] {
+			f.received = append(f.received, data[:len(data)/2]...)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		f.received = append(f.received, data...)
+	}
+
+	if f.total >= 0 && int64(len(f.received)) == f.total {
+		f.done = true
+		json.NewEncoder(w).Encode(&drive.File{Id: "fileid", Name: f.meta.Name, MimeType: f.meta.MimeType})
+		return
+	}
+	if len(f.received) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(f.received)-1))
+	}
+	w.WriteHeader(http.StatusPermanentRedirect)
+}
+
+// newFakeUploads returns a Service which uploads to a fakeUploads server
+func newFakeUploads(t *testing.T) (*Service, *fakeUploads) {
+	fake := &fakeUploads{t: t, failPuts: map[int]bool{}}
+	return newTestService(t, fake), fake
+}
+
+// unsized hides the size of a reader
+type unsized struct {
+	io.Reader
+}
+
+func TestResumableUpload(t *testing.T) {
+	data := make([]byte, 3*minChunkSize+1000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	tests := []struct {
+		name     string
+		size     int
+		sized    bool
+		failPuts []int
+		wantPuts int
+	}{
+		{"sized", len(data), true, nil, 4},
+		{"unsized", len(data), false, nil, 4},
+		{"retry", len(data), true, []int{2, 4}, 8},
+		{"retry last", len(data), false, []int{4}, 6},
+		{"exact chunks", 2 * minChunkSize, false, nil, 2},
+		{"empty", 0, false, nil, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc, fake := newFakeUploads(t)
+			for _, n := range test.failPuts {
+				fake.failPuts[n] = true
+			}
+			var progress []int64
+			opts := &UploadOptions{
+				ChunkSize: 1,  // rounded up to minChunkSize
+				Retries:   -1, // the default
+				Progress: func(sent, total int64) {
+					progress = append(progress, sent)
+				},
+			}
+			meta := &drive.File{Name: "data.csv", MimeType: "application/vnd.google-apps.spreadsheet"}
+			var src io.Reader = bytes.NewReader(data[:test.size])
+			if !test.sized {
+				src = unsized{src}
+			}
+			file, err := svc.uploadResumable("POST", svc.uploadEndpoint(), meta, src, "text/csv", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			size := int64(test.size)
+			if !fake.done || !bytes.Equal(fake.received, data[:size]) {
+				t.Errorf("server got %d bytes (done: %v), want %d", len(fake.received), fake.done, size)
+			}
+			if file.Id != "fileid" || fake.meta.Name != "data.csv" || fake.meta.MimeType != meta.MimeType {
+				t.Errorf("got file %+v with metadata %+v", file, fake.meta)
+			}
+			if fake.puts != test.wantPuts {
+				t.Errorf("got %d PUTs, want %d", fake.puts, test.wantPuts)
+			}
+			for i := 1; i < len(progress); i++ {
+				if progress[i] <= progress[i-1] {
+					t.Errorf("progress went backwards: %v", progress)
+				}
+			}
+			if size > 0 && (len(progress) == 0 || progress[len(progress)-1] != size) {
+				t.Errorf("progress ended at %v, want %d", progress, size)
+			}
+		})
+	}
+}
+
+func TestResumableUploadGivesUp(t *testing.T) {
+	svc, fake := newFakeUploads(t)
+	fake.down = true
+	_, err := svc.uploadResumable("POST", svc.uploadEndpoint(), &drive.File{Name: "x"},
+		strings.NewReader("some data"), "text/plain", &UploadOptions{Retries: 2})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if fake.puts != 3 {
+		t.Errorf("got %d PUTs, want 3", fake.puts)
+	}
+}
+
+func TestResumableUploadGivesUpResending(t *testing.T) {
+	svc, fake := newFakeUploads(t)
+	fake.failData = true
+	_, err := svc.uploadResumable("POST", svc.uploadEndpoint(), &drive.File{Name: "x"},
+		strings.NewReader("some data"), "text/plain", &UploadOptions{Retries: 2})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// the data, then a status query and the data again for each retry
+	if fake.puts != 5 {
+		t.Errorf("got %d PUTs, want 5", fake.puts)
+	}
+}
+
+func TestResumableUploadNoRetries(t *testing.T) {
+	svc, fake := newFakeUploads(t)
+	fake.down = true
+	_, err := svc.uploadResumable("POST", svc.uploadEndpoint(), &drive.File{Name: "x"},
+		strings.NewReader("some data"), "text/plain", &UploadOptions{Retries: 0})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if fake.puts != 1 {
+		t.Errorf("got %d PUTs, want 1", fake.puts)
+	}
+}
+
+func TestResumableUpdate(t *testing.T) {
+	svc, fake := newFakeUploads(t)
+	file, err := svc.updateFile("abc", "new.txt", "text/plain", strings.NewReader("hello"),
+		&UploadOptions{ChunkSize: minChunkSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.method != "PATCH" || fake.path != "/upload/drive/v3/files/abc" || file.Name != "new.txt" {
+		t.Errorf("got %s %s and file %+v", fake.method, fake.path, file)
+	}
+	if string(fake.received) != "hello" {
+		t.Errorf("server got %q", fake.received)
+	}
+}
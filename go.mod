@@ -5,6 +5,7 @@ go 1.19
 require (
 	github.com/urfave/cli/v2 v2.27.2
 	google.golang.org/api v0.183.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
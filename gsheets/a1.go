@@ -0,0 +1,193 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// matches the cell part of an A1 reference (eg "A1", "C", "12")
+var a1Cell = regexp.MustCompile(`^([A-Za-z]*)([0-9]*)$`)
+
+// matches a column's letters (eg "C", "AB")
+var a1Column = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// splitA1 splits 'a1Range' into its sheet title and cell range parts.
+// If there is no '!' the whole range is returned as the title; callers must
+// decide whether it is really a sheet title or a range on the first sheet.
+// Quoted sheet titles are unquoted.
+func splitA1(a1Range string) (title, cells string) {
+	i := strings.LastIndex(a1Range, "!")
+	if i < 0 || strings.HasSuffix(a1Range, "'") {
+		return unquoteTitle(a1Range), ""
+	}
+	return unquoteTitle(a1Range[:i]), a1Range[i+1:]
+}
+
+// unquoteTitle removes the single quotes around a sheet title and unescapes
+// any doubled quotes within it
+func unquoteTitle(title string) string {
+	if len(title) >= 2 && strings.HasPrefix(title, "'") && strings.HasSuffix(title, "'") {
+		title = strings.ReplaceAll(title[1:len(title)-1], "''", "'")
+	}
+	return title
+}
+
+// quoteTitle quotes a sheet title for use in A1 notation
+func quoteTitle(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
+
+// isA1Cells reports whether 's' looks like the cell part of an A1 reference
+// ("A1", "A1:B2", "A:C", "2:5")
+func isA1Cells(s string) bool {
+	if s == "" {
+		return false
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, p := range parts {
+		m := a1Cell.FindStringSubmatch(p)
+		if m == nil || (m[1] == "" && m[2] == "") {
+			return false
+		}
+	}
+	return true
+}
+
+// columnIndex converts column letters to a zero-based index (A=0, B=1, ...)
+func columnIndex(letters string) int64 {
+	var idx int64
+	for _, r := range strings.ToUpper(letters) {
+		idx = idx*26 + int64(r-'A'+1)
+	}
+	return idx - 1
+}
+
+// columnLetters converts a zero-based column index to letters (0=A, 1=B, ...)
+func columnLetters(idx int64) string {
+	var letters string
+	for idx++; idx > 0; idx = (idx - 1) / 26 {
+		letters = string(rune('A'+(idx-1)%26)) + letters
+	}
+	return letters
+}
+
+// parseCells sets the row and column bounds of 'gr' from the cell part of an
+// A1 reference. Missing bounds are left unset (unbounded).
+func parseCells(cells string, gr *sheets.GridRange) error {
+	if cells == "" {
+		return nil
+	}
+	if !isA1Cells(cells) {
+		return fmt.Errorf("Invalid A1 range: %s", cells)
+	}
+	parts := strings.Split(cells, ":")
+	start := a1Cell.FindStringSubmatch(parts[0])
+	end := start
+	if len(parts) == 2 {
+		end = a1Cell.FindStringSubmatch(parts[1])
+	}
+	if start[1] != "" {
+		gr.StartColumnIndex = columnIndex(start[1])
+	}
+	if start[2] != "" {
+		row, _ := strconv.ParseInt(start[2], 10, 64)
+		gr.StartRowIndex = row - 1
+	}
+	if end[1] != "" {
+		gr.EndColumnIndex = columnIndex(end[1]) + 1
+	}
+	if end[2] != "" {
+		row, _ := strconv.ParseInt(end[2], 10, 64)
+		gr.EndRowIndex = row
+	}
+	return nil
+}
+
+// A1FromGridRange returns 'gr' in A1 notation on the sheet titled 'title'.
+// Unbounded rows or columns are omitted ("Sheet1!A2:C", "Sheet1!A:C")
+func A1FromGridRange(title string, gr *sheets.GridRange) string {
+	a1 := quoteTitle(title)
+	cols := gr.StartColumnIndex > 0 || gr.EndColumnIndex > 0
+	rows := gr.StartRowIndex > 0 || gr.EndRowIndex > 0
+	var start, end string
+	if cols {
+		start = columnLetters(gr.StartColumnIndex)
+		if gr.EndColumnIndex > 0 {
+			end = columnLetters(gr.EndColumnIndex - 1)
+		}
+	}
+	if rows || (cols && gr.EndColumnIndex == 0) {
+		start += strconv.FormatInt(gr.StartRowIndex+1, 10)
+	}
+	if rows && gr.EndRowIndex > 0 {
+		end += strconv.FormatInt(gr.EndRowIndex, 10)
+	}
+	switch {
+	case start == "":
+		return a1
+	case end == "":
+		return a1 + "!" + start + ":" + start
+	}
+	return a1 + "!" + start + ":" + end
+}
+
+// GridRange converts 'a1Range' into a GridRange on the spreadsheet doc
+// identified by 'id'.
+// 'a1Range' may also be the name of a named range.
+// If 'a1Range' does not name a sheet, the first sheet is used.
+// A1 syntax: https://developers.google.com/sheets/api/guides/concepts
+func (svc *Service) GridRange(id, a1Range string) (*sheets.GridRange, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return gridRange(ss, a1Range)
+}
+
+// gridRange resolves 'a1Range' against the sheets and named ranges of 'ss'
+func gridRange(ss *sheets.Spreadsheet, a1Range string) (*sheets.GridRange, error) {
+	if len(ss.Sheets) == 0 {
+		return nil, errors.New("No sheets found in spreadsheet")
+	}
+
+	title, cells := splitA1(a1Range)
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties.Title == title {
+			gr := &sheets.GridRange{SheetId: sheet.Properties.SheetId}
+			return gr, parseCells(cells, gr)
+		}
+	}
+	if !strings.Contains(a1Range, "!") {
+		for _, nr := range ss.NamedRanges {
+			if nr.Name == a1Range {
+				gr := *nr.Range
+				return &gr, nil
+			}
+		}
+		if isA1Cells(a1Range) {
+			// a range without a sheet title refers to the first sheet
+			gr := &sheets.GridRange{SheetId: ss.Sheets[0].Properties.SheetId}
+			return gr, parseCells(a1Range, gr)
+		}
+	}
+	return nil, fmt.Errorf("No sheet or named range %s found", title)
+}
+
+// sheetTitle returns the title of the sheet with 'sheetId' in 'ss' (or the
+// empty string if there is no such sheet)
+func sheetTitle(ss *sheets.Spreadsheet, sheetId int64) string {
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties.SheetId == sheetId {
+			return sheet.Properties.Title
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package gsheets
+
+import (
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestParseCells(t *testing.T) {
+	tests := []struct {
+		cells string
+		want  sheets.GridRange
+	}{
+		{"A1", sheets.GridRange{EndRowIndex: 1, EndColumnIndex: 1}},
+		{"B2:D5", sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5, StartColumnIndex: 1, EndColumnIndex: 4}},
+		{"A2:C", sheets.GridRange{StartRowIndex: 1, EndColumnIndex: 3}},
+		{"A:C", sheets.GridRange{EndColumnIndex: 3}},
+		{"2:5", sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5}},
+		{"AA1:AB2", sheets.GridRange{EndRowIndex: 2, StartColumnIndex: 26, EndColumnIndex: 28}},
+	}
+	for _, tt := range tests {
+		var got sheets.GridRange
+		if err := parseCells(tt.cells, &got); err != nil {
+			t.Fatalf("%s: %v", tt.cells, err)
+		}
+		if got.StartRowIndex != tt.want.StartRowIndex || got.EndRowIndex != tt.want.EndRowIndex ||
+			got.StartColumnIndex != tt.want.StartColumnIndex || got.EndColumnIndex != tt.want.EndColumnIndex {
+			t.Errorf("%s: got %+v, want %+v", tt.cells, got, tt.want)
+		}
+	}
+
+	if err := parseCells("A1:B2:C3", &sheets.GridRange{}); err == nil {
+		t.Error("expected error for invalid range")
+	}
+}
+
+func TestA1FromGridRange(t *testing.T) {
+	tests := []struct {
+		gr   sheets.GridRange
+		want string
+	}{
+		{sheets.GridRange{}, "'Sheet1'"},
+		{sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5, StartColumnIndex: 1, EndColumnIndex: 4}, "'Sheet1'!B2:D5"},
+		{sheets.GridRange{StartRowIndex: 1, EndColumnIndex: 3}, "'Sheet1'!A2:C"},
+		{sheets.GridRange{StartRowIndex: 1, EndRowIndex: 5}, "'Sheet1'!2:5"},
+	}
+	for _, tt := range tests {
+		if got := A1FromGridRange("Sheet1", &tt.gr); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestColumnLetters(t *testing.T) {
+	for idx, letters := range map[int64]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := columnLetters(idx); got != letters {
+			t.Errorf("columnLetters(%d) = %s, want %s", idx, got, letters)
+		}
+		if got := columnIndex(letters); got != idx {
+			t.Errorf("columnIndex(%s) = %d, want %d", letters, got, idx)
+		}
+	}
+}
+
+func TestSplitA1(t *testing.T) {
+	tests := []struct {
+		a1, title, cells string
+	}{
+		{"Sheet1!A1:B2", "Sheet1", "A1:B2"},
+		{"Sheet1", "Sheet1", ""},
+		{"A1:B2", "A1:B2", ""},
+		{"'My Sheet'!A:C", "My Sheet", "A:C"},
+		{"'it''s'!B2", "it's", "B2"},
+		// a '!' inside a quoted title is part of the title
+		{"'a!b'", "a!b", ""},
+		{"'a!b'!A1", "a!b", "A1"},
+	}
+	for _, tt := range tests {
+		title, cells := splitA1(tt.a1)
+		if title != tt.title || cells != tt.cells {
+			t.Errorf("splitA1(%q) = %q, %q, want %q, %q", tt.a1, title, cells, tt.title, tt.cells)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Batch collects operations on a spreadsheet doc and sends them in a single
+// batchUpdate call by Do. Either all of the operations are made or (if any
+// fails) none of them are.
+//
+// Sheet titles and A1 ranges are resolved when an operation is added, and
+// operations may refer to sheets added earlier in the same batch. The first
+// error encountered while adding operations is returned by Do and no request
+// is sent.
+//
+// Operations which produce a result (such as AddSheet) return a pointer which
+// is filled in when Do succeeds:
+//
+//	b := svc.NewBatch(id)
+//	sheet := b.AddSheet("Report")
+//	b.Format("Report!1:1", &gsheets.CellFormat{Bold: &bold})
+//	b.Freeze("Report", 1, 0)
+//	err := b.Do()
+//	// sheet.Id is the id of the new sheet
+type Batch struct {
+	svc     *Service
+	id      string
+	ss      *sheets.Spreadsheet
+	reqs    []*sheets.Request
+	replies []func(*sheets.Response)
+	err     error
+}
+
+// AddedSheet is the result of Batch.AddSheet
+type AddedSheet struct {
+	Id    int64
+	Title string
+	Index int64
+}
+
+// NewBatch returns an empty Batch of operations on the spreadsheet doc
+// identified by 'id'
+func (svc *Service) NewBatch(id string) *Batch {
+	b := &Batch{svc: svc, id: id}
+	if id == "" {
+		b.err = errors.New("id cannot be empty")
+	}
+	return b
+}
+
+// Len returns the number of requests in the batch
+func (b *Batch) Len() int {
+	return len(b.reqs)
+}
+
+// Err returns the first error encountered while adding operations
+func (b *Batch) Err() error {
+	return b.err
+}
+
+// spreadsheet fetches the sheets and named ranges of the document the first
+// time they are needed to resolve a title or range
+func (b *Batch) spreadsheet() (*sheets.Spreadsheet, error) {
+	if b.ss != nil {
+		return b.ss, nil
+	}
+	ss, err := b.svc.sheet.Get(b.id).Fields("sheets.properties,namedRanges").Context(b.svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	b.ss = ss
+	return ss, nil
+}
+
+// gridRange resolves 'a1Range' against the document (and any sheets added by
+// the batch)
+func (b *Batch) gridRange(a1Range string) (*sheets.GridRange, error) {
+	ss, err := b.spreadsheet()
+	if err != nil {
+		return nil, err
+	}
+	return gridRange(ss, a1Range)
+}
+
+// sheetId returns the id of the sheet with 'title'
+func (b *Batch) sheetId(title string) (int64, error) {
+	ss, err := b.spreadsheet()
+	if err != nil {
+		return 0, err
+	}
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties.Title == title {
+			return sheet.Properties.SheetId, nil
+		}
+	}
+	return 0, fmt.Errorf("No sheet titled %s found", title)
+}
+
+// fail records 'err' if it is the first error in the batch. It returns true
+// if the batch has failed.
+func (b *Batch) fail(err error) bool {
+	if b.err == nil {
+		b.err = err
+	}
+	return b.err != nil
+}
+
+// add appends 'req' to the batch. If 'reply' is not nil it is called with the
+// request's response after Do succeeds.
+func (b *Batch) add(req *sheets.Request, reply func(*sheets.Response)) {
+	b.reqs = append(b.reqs, req)
+	b.replies = append(b.replies, reply)
+}
+
+// Add appends raw requests to the batch for operations which Batch does not
+// wrap
+func (b *Batch) Add(reqs ...*sheets.Request) *Batch {
+	for _, req := range reqs {
+		b.add(req, nil)
+	}
+	return b
+}
+
+// AddSheet adds a new sheet titled 'title'. Later operations in the batch can
+// refer to it by title.
+func (b *Batch) AddSheet(title string) *AddedSheet {
+	added := &AddedSheet{Title: title}
+	ss, err := b.spreadsheet()
+	if b.fail(err) {
+		return added
+	}
+	// choose the new sheet's id so that later operations can refer to it
+	used := make(map[int64]bool)
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties.Title == title {
+			b.fail(fmt.Errorf("A sheet titled %s already exists", title))
+			return added
+		}
+		used[sheet.Properties.SheetId] = true
+	}
+	var sheetId int64 = 1
+	for used[sheetId] {
+		sheetId++
+	}
+	props := &sheets.SheetProperties{SheetId: sheetId, Title: title, SheetType: "GRID"}
+	ss.Sheets = append(ss.Sheets, &sheets.Sheet{Properties: props})
+
+	b.add(&sheets.Request{
+		AddSheet: &sheets.AddSheetRequest{
+			Properties: &sheets.SheetProperties{SheetId: sheetId, Title: title},
+		},
+	}, func(resp *sheets.Response) {
+		props := resp.AddSheet.Properties
+		added.Id, added.Title, added.Index = props.SheetId, props.Title, props.Index
+	})
+	return added
+}
+
+// DeleteSheet deletes the sheet titled 'title'
+func (b *Batch) DeleteSheet(title string) *Batch {
+	sheetId, err := b.sheetId(title)
+	if b.fail(err) {
+		return b
+	}
+	sheetList := b.ss.Sheets[:0]
+	for _, sheet := range b.ss.Sheets {
+		if sheet.Properties.SheetId != sheetId {
+			sheetList = append(sheetList, sheet)
+		}
+	}
+	b.ss.Sheets = sheetList
+	b.add(&sheets.Request{
+		DeleteSheet: &sheets.DeleteSheetRequest{SheetId: sheetId},
+	}, nil)
+	return b
+}
+
+// Sort sorts the sheet titled 'title' by 'column'.
+// If asc is true, sort ascending; otherwise sort descending
+// Column is the column index rather than A1 notation (0=A, 1=B, ...)
+func (b *Batch) Sort(title string, asc bool, column int64) *Batch {
+	sheetId, err := b.sheetId(title)
+	if b.fail(err) {
+		return b
+	}
+	order := "DESCENDING"
+	if asc {
+		order = "ASCENDING"
+	}
+	b.add(&sheets.Request{
+		SortRange: &sheets.SortRangeRequest{
+			Range: &sheets.GridRange{SheetId: sheetId},
+			SortSpecs: []*sheets.SortSpec{
+				{
+					DimensionIndex:  column,
+					SortOrder:       order,
+					ForceSendFields: []string{"DimensionIndex"},
+				},
+			},
+		},
+	}, nil)
+	return b
+}
+
+// Clear clears the values (but not the formatting) of the cells in 'a1Range'
+func (b *Batch) Clear(a1Range string) *Batch {
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return b
+	}
+	b.add(&sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{Range: gr, Fields: "userEnteredValue"},
+	}, nil)
+	return b
+}
+
+// Format applies 'format' to every cell in 'a1Range' (see FormatRange)
+func (b *Batch) Format(a1Range string, format *CellFormat) *Batch {
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return b
+	}
+	req, err := formatRequest(gr, format)
+	if b.fail(err) {
+		return b
+	}
+	b.add(req, nil)
+	return b
+}
+
+// SetValidation sets the data validation rule 'v' on every cell in 'a1Range'.
+// If 'v' is nil any validation is cleared.
+func (b *Batch) SetValidation(a1Range string, v *Validation) *Batch {
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return b
+	}
+	req, err := validationRequest(gr, v)
+	if b.fail(err) {
+		return b
+	}
+	b.add(req, nil)
+	return b
+}
+
+// Freeze freezes the first 'rows' rows and 'cols' columns of the sheet titled
+// 'title' (see Service.Freeze)
+func (b *Batch) Freeze(title string, rows, cols int64) *Batch {
+	sheetId, err := b.sheetId(title)
+	if b.fail(err) {
+		return b
+	}
+	req, err := freezeRequest(sheetId, rows, cols)
+	if b.fail(err) {
+		return b
+	}
+	b.add(req, nil)
+	return b
+}
+
+// insert inserts 'count' empty rows or columns before 'index' (zero-based) of
+// the sheet titled 'title'
+func (b *Batch) insert(dimension, title string, index, count int64) *Batch {
+	if count < 1 {
+		b.fail(fmt.Errorf("Cannot insert %d %s", count, dimension))
+		return b
+	}
+	sheetId, err := b.sheetId(title)
+	if b.fail(err) {
+		return b
+	}
+	b.add(&sheets.Request{
+		InsertDimension: &sheets.InsertDimensionRequest{
+			Range: &sheets.DimensionRange{
+				SheetId:         sheetId,
+				Dimension:       dimension,
+				StartIndex:      index,
+				EndIndex:        index + count,
+				ForceSendFields: []string{"StartIndex"},
+			},
+			// new rows/columns take the format of the ones before them
+			InheritFromBefore: index > 0,
+		},
+	}, nil)
+	return b
+}
+
+// InsertRows inserts 'count' empty rows before row 'index' (zero-based, so 0
+// inserts before row 1) of the sheet titled 'title'
+func (b *Batch) InsertRows(title string, index, count int64) *Batch {
+	return b.insert("ROWS", title, index, count)
+}
+
+// InsertColumns inserts 'count' empty columns before column 'index'
+// (zero-based, so 0 inserts before column A) of the sheet titled 'title'
+func (b *Batch) InsertColumns(title string, index, count int64) *Batch {
+	return b.insert("COLUMNS", title, index, count)
+}
+
+// DeleteRange deletes the whole rows ("Sheet1!3:5") or whole columns
+// ("Sheet1!C:D") in 'a1Range', shifting the rest of the sheet up or left
+func (b *Batch) DeleteRange(a1Range string) *Batch {
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return b
+	}
+	dr, err := dimensionRange(gr)
+	if b.fail(err) {
+		return b
+	}
+	b.add(&sheets.Request{
+		DeleteDimension: &sheets.DeleteDimensionRequest{Range: dr},
+	}, nil)
+	return b
+}
+
+// Do sends all of the operations in the batch in a single atomic update and
+// fills in their results.
+// A batch without operations does nothing.
+func (b *Batch) Do() error {
+	if b.err != nil {
+		return b.err
+	}
+	if len(b.reqs) == 0 {
+		return nil
+	}
+	resp, err := b.svc.batchUpdate(b.id, b.reqs...)
+	if err != nil {
+		return err
+	}
+	for i, reply := range b.replies {
+		if reply != nil && i < len(resp.Replies) {
+			reply(resp.Replies[i])
+		}
+	}
+	return nil
+}
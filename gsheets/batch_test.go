@@ -0,0 +1,81 @@
+package gsheets
+
+import (
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestBatchResolvesAddedSheets(t *testing.T) {
+	svc := &Service{}
+	b := svc.NewBatch("doc")
+	b.ss = &sheets.Spreadsheet{
+		Sheets: []*sheets.Sheet{
+			{Properties: &sheets.SheetProperties{SheetId: 1, Title: "Data"}},
+		},
+	}
+	sheet := b.AddSheet("Report")
+	b.Format("Report!1:1", &CellFormat{HorizontalAlignment: "center"})
+	b.Freeze("Report", 1, -1)
+	b.InsertRows("Data", 0, 2)
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 4 {
+		t.Fatalf("got %d requests, want 4", b.Len())
+	}
+	newId := b.reqs[0].AddSheet.Properties.SheetId
+	if newId != 2 {
+		t.Errorf("new sheet id: got %d, want 2", newId)
+	}
+	if id := b.reqs[1].RepeatCell.Range.SheetId; id != newId {
+		t.Errorf("format sheet id: got %d, want %d", id, newId)
+	}
+
+	// replies are mapped back to the operation's result
+	b.replies[0](&sheets.Response{AddSheet: &sheets.AddSheetResponse{
+		Properties: &sheets.SheetProperties{SheetId: newId, Title: "Report", Index: 1},
+	}})
+	if sheet.Id != newId || sheet.Index != 1 {
+		t.Errorf("added sheet: got %+v", sheet)
+	}
+}
+
+func TestBatchFirstErrorWins(t *testing.T) {
+	b := (&Service{}).NewBatch("doc")
+	b.ss = &sheets.Spreadsheet{
+		Sheets: []*sheets.Sheet{
+			{Properties: &sheets.SheetProperties{SheetId: 1, Title: "Data"}},
+		},
+	}
+	b.DeleteSheet("Missing")
+	b.AddSheet("Data")
+	if err := b.Do(); err == nil || err.Error() != "No sheet titled Missing found" {
+		t.Errorf("got error %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("got %d requests, want 0", b.Len())
+	}
+}
+
+func TestBatchDeleteDuplicatesColumns(t *testing.T) {
+	b := (&Service{}).NewBatch("doc")
+	b.ss = &sheets.Spreadsheet{
+		Sheets: []*sheets.Sheet{
+			{Properties: &sheets.SheetProperties{SheetId: 3, Title: "Data"}},
+		},
+	}
+	removed := b.DeleteDuplicates("Data!A2:E", "A", "C:D")
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
+	cols := b.reqs[0].DeleteDuplicates.ComparisonColumns
+	if len(cols) != 2 || cols[0].StartIndex != 0 || cols[0].EndIndex != 1 ||
+		cols[1].StartIndex != 2 || cols[1].EndIndex != 4 || cols[1].SheetId != 3 {
+		t.Errorf("comparison columns: got %+v %+v", cols[0], cols[1])
+	}
+	b.replies[0](&sheets.Response{DeleteDuplicates: &sheets.DeleteDuplicatesResponse{DuplicatesRemovedCount: 4}})
+	if *removed != 4 {
+		t.Errorf("removed: got %d, want 4", *removed)
+	}
+}
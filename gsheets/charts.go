@@ -0,0 +1,274 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// ChartSpec describes a basic chart over a source range. The first column of
+// Source is used as the domain (x-axis or pie slices) and each remaining
+// column as a series.
+// Type is one of LINE, BAR, COLUMN, PIE, SCATTER or AREA.
+// If Anchor is set (a cell in A1 notation, eg "Sheet1!F2") the chart floats
+// over that sheet with its top-left corner at the cell; otherwise it is placed
+// on a new sheet of its own.
+// The yaml/json tags allow chart specs to be kept in files.
+type ChartSpec struct {
+	Title      string `yaml:"title,omitempty" json:"title,omitempty"`
+	Type       string `yaml:"type" json:"type"`
+	Source     string `yaml:"source" json:"source"`
+	HeaderRows int64  `yaml:"headerRows,omitempty" json:"headerRows,omitempty"`
+	Anchor     string `yaml:"anchor,omitempty" json:"anchor,omitempty"`
+	// Legend is one of BOTTOM_LEGEND, LEFT_LEGEND, RIGHT_LEGEND, TOP_LEGEND or
+	// NO_LEGEND
+	Legend string `yaml:"legend,omitempty" json:"legend,omitempty"`
+	Width  int64  `yaml:"width,omitempty" json:"width,omitempty"`
+	Height int64  `yaml:"height,omitempty" json:"height,omitempty"`
+}
+
+// Chart is a chart in a spreadsheet doc
+type Chart struct {
+	Id         int64  `json:"id"`
+	SheetId    int64  `json:"sheetId"`
+	SheetTitle string `json:"sheetTitle"`
+	OwnSheet   bool   `json:"ownSheet"`
+	ChartSpec
+}
+
+// chartData returns ChartData for the column 'col' of 'gr'
+func chartData(gr *sheets.GridRange, col int64) *sheets.ChartData {
+	src := *gr
+	src.StartColumnIndex = col
+	src.EndColumnIndex = col + 1
+	return &sheets.ChartData{
+		SourceRange: &sheets.ChartSourceRange{
+			Sources: []*sheets.GridRange{&src},
+		},
+	}
+}
+
+// toSheets converts the ChartSpec into a sheets.ChartSpec, resolving its source
+// range against 'ss'
+func (spec *ChartSpec) toSheets(ss *sheets.Spreadsheet) (*sheets.ChartSpec, error) {
+	gr, err := gridRange(ss, spec.Source)
+	if err != nil {
+		return nil, err
+	}
+	if gr.EndColumnIndex == 0 {
+		return nil, fmt.Errorf("Chart source must have bounded columns: %s", spec.Source)
+	}
+	if gr.EndColumnIndex-gr.StartColumnIndex < 2 {
+		return nil, errors.New("Chart source needs a domain column and at least one series column")
+	}
+
+	cs := &sheets.ChartSpec{Title: spec.Title}
+	domain := chartData(gr, gr.StartColumnIndex)
+	chartType := strings.ToUpper(spec.Type)
+	switch chartType {
+	case "PIE":
+		cs.PieChart = &sheets.PieChartSpec{
+			Domain:         domain,
+			Series:         chartData(gr, gr.StartColumnIndex+1),
+			LegendPosition: spec.Legend,
+		}
+	case "LINE", "BAR", "COLUMN", "SCATTER", "AREA":
+		basic := &sheets.BasicChartSpec{
+			ChartType:      chartType,
+			HeaderCount:    spec.HeaderRows,
+			LegendPosition: spec.Legend,
+			Domains:        []*sheets.BasicChartDomain{{Domain: domain}},
+		}
+		for col := gr.StartColumnIndex + 1; col < gr.EndColumnIndex; col++ {
+			basic.Series = append(basic.Series, &sheets.BasicChartSeries{
+				Series: chartData(gr, col),
+			})
+		}
+		cs.BasicChart = basic
+	default:
+		return nil, fmt.Errorf("Unknown chart type: %s", spec.Type)
+	}
+	return cs, nil
+}
+
+// position returns the EmbeddedObjectPosition for the chart, resolving its
+// anchor against 'ss'
+func (spec *ChartSpec) position(ss *sheets.Spreadsheet) (*sheets.EmbeddedObjectPosition, error) {
+	if spec.Anchor == "" {
+		return &sheets.EmbeddedObjectPosition{NewSheet: true}, nil
+	}
+	gr, err := gridRange(ss, spec.Anchor)
+	if err != nil {
+		return nil, err
+	}
+	return &sheets.EmbeddedObjectPosition{
+		OverlayPosition: &sheets.OverlayPosition{
+			AnchorCell: &sheets.GridCoordinate{
+				SheetId:     gr.SheetId,
+				RowIndex:    gr.StartRowIndex,
+				ColumnIndex: gr.StartColumnIndex,
+			},
+			WidthPixels:  spec.Width,
+			HeightPixels: spec.Height,
+		},
+	}, nil
+}
+
+// sourceBounds returns the smallest GridRange containing all of 'data'
+func sourceBounds(gr *sheets.GridRange, data *sheets.ChartData) *sheets.GridRange {
+	if data == nil || data.SourceRange == nil {
+		return gr
+	}
+	for _, src := range data.SourceRange.Sources {
+		if gr == nil {
+			cp := *src
+			gr = &cp
+			continue
+		}
+		if src.StartColumnIndex < gr.StartColumnIndex {
+			gr.StartColumnIndex = src.StartColumnIndex
+		}
+		if src.EndColumnIndex > gr.EndColumnIndex {
+			gr.EndColumnIndex = src.EndColumnIndex
+		}
+		if src.StartRowIndex < gr.StartRowIndex {
+			gr.StartRowIndex = src.StartRowIndex
+		}
+		if src.EndRowIndex == 0 || (gr.EndRowIndex != 0 && src.EndRowIndex > gr.EndRowIndex) {
+			gr.EndRowIndex = src.EndRowIndex
+		}
+	}
+	return gr
+}
+
+// newChart converts 'ec' back into a Chart, resolving ranges against 'ss'
+func newChart(ss *sheets.Spreadsheet, sheetId int64, ec *sheets.EmbeddedChart) *Chart {
+	chart := &Chart{Id: ec.ChartId, SheetId: sheetId}
+	chart.SheetTitle = sheetTitle(ss, sheetId)
+	if ec.Position != nil {
+		if op := ec.Position.OverlayPosition; op != nil && op.AnchorCell != nil {
+			chart.Anchor = A1FromGridRange(sheetTitle(ss, op.AnchorCell.SheetId), &sheets.GridRange{
+				StartRowIndex:    op.AnchorCell.RowIndex,
+				EndRowIndex:      op.AnchorCell.RowIndex + 1,
+				StartColumnIndex: op.AnchorCell.ColumnIndex,
+				EndColumnIndex:   op.AnchorCell.ColumnIndex + 1,
+			})
+			chart.Width = op.WidthPixels
+			chart.Height = op.HeightPixels
+		} else {
+			chart.OwnSheet = true
+		}
+	}
+	if ec.Spec == nil {
+		return chart
+	}
+
+	chart.Title = ec.Spec.Title
+	var bounds *sheets.GridRange
+	switch {
+	case ec.Spec.BasicChart != nil:
+		basic := ec.Spec.BasicChart
+		chart.Type = basic.ChartType
+		chart.HeaderRows = basic.HeaderCount
+		chart.Legend = basic.LegendPosition
+		for _, d := range basic.Domains {
+			bounds = sourceBounds(bounds, d.Domain)
+		}
+		for _, s := range basic.Series {
+			bounds = sourceBounds(bounds, s.Series)
+		}
+	case ec.Spec.PieChart != nil:
+		chart.Type = "PIE"
+		chart.Legend = ec.Spec.PieChart.LegendPosition
+		bounds = sourceBounds(bounds, ec.Spec.PieChart.Domain)
+		bounds = sourceBounds(bounds, ec.Spec.PieChart.Series)
+	}
+	if bounds != nil {
+		chart.Source = A1FromGridRange(sheetTitle(ss, bounds.SheetId), bounds)
+	}
+	return chart
+}
+
+// Charts returns all of the charts in the spreadsheet doc identified by 'id'
+func (svc *Service) Charts(id string) ([]*Chart, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets(properties,charts)").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	var charts []*Chart
+	for _, sheet := range ss.Sheets {
+		for _, ec := range sheet.Charts {
+			charts = append(charts, newChart(ss, sheet.Properties.SheetId, ec))
+		}
+	}
+	return charts, nil
+}
+
+// AddChart creates a chart described by 'spec' in the spreadsheet doc
+// identified by 'id' and returns the new chart's id
+func (svc *Service) AddChart(id string, spec *ChartSpec) (int64, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return 0, err
+	}
+	cs, err := spec.toSheets(ss)
+	if err != nil {
+		return 0, err
+	}
+	pos, err := spec.position(ss)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := svc.batchUpdate(id, &sheets.Request{
+		AddChart: &sheets.AddChartRequest{
+			Chart: &sheets.EmbeddedChart{Spec: cs, Position: pos},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Replies[0].AddChart.Chart.ChartId, nil
+}
+
+// UpdateChart replaces the chart identified by 'chartId' in the spreadsheet
+// doc identified by 'id' with 'spec'.
+// If 'spec' has an Anchor the chart is also moved there.
+func (svc *Service) UpdateChart(id string, chartId int64, spec *ChartSpec) error {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	cs, err := spec.toSheets(ss)
+	if err != nil {
+		return err
+	}
+	reqs := []*sheets.Request{
+		{UpdateChartSpec: &sheets.UpdateChartSpecRequest{ChartId: chartId, Spec: cs}},
+	}
+	if spec.Anchor != "" {
+		pos, err := spec.position(ss)
+		if err != nil {
+			return err
+		}
+		reqs = append(reqs, &sheets.Request{
+			UpdateEmbeddedObjectPosition: &sheets.UpdateEmbeddedObjectPositionRequest{
+				ObjectId:    chartId,
+				NewPosition: pos,
+				Fields:      "*",
+			},
+		})
+	}
+	_, err = svc.batchUpdate(id, reqs...)
+	return err
+}
+
+// DeleteChart deletes the chart identified by 'chartId' from the spreadsheet
+// doc identified by 'id'
+func (svc *Service) DeleteChart(id string, chartId int64) error {
+	_, err := svc.batchUpdate(id, &sheets.Request{
+		DeleteEmbeddedObject: &sheets.DeleteEmbeddedObjectRequest{ObjectId: chartId},
+	})
+	return err
+}
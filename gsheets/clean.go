@@ -0,0 +1,143 @@
+package gsheets
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// delimiterStrings are the delimiters TextToColumns knows by name and the
+// strings each splits on (for AUTODETECT, any which might be detected)
+var delimiterStrings = map[string][]string{
+	"COMMA":      {","},
+	"SEMICOLON":  {";"},
+	"PERIOD":     {"."},
+	"SPACE":      {" "},
+	"AUTODETECT": {",", ";", ".", " "},
+}
+
+// splits reports whether 'text' contains 'delimiter' (as for TextToColumns),
+// and so would be split by it
+func splits(text, delimiter string) bool {
+	seps, ok := delimiterStrings[strings.ToUpper(delimiter)]
+	if !ok {
+		seps = []string{delimiter}
+	}
+	for _, sep := range seps {
+		if sep != "" && strings.Contains(text, sep) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteDuplicates removes rows in 'a1Range' which duplicate an earlier row
+// in the range. If 'columns' are given (as column letters, eg "A", "C:D") only
+// those columns are compared; otherwise whole rows must match.
+// The result is the number of rows removed.
+func (b *Batch) DeleteDuplicates(a1Range string, columns ...string) *int64 {
+	removed := new(int64)
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return removed
+	}
+	req := &sheets.DeleteDuplicatesRequest{Range: gr}
+	for _, col := range columns {
+		if !isA1Cells(col) || strings.ContainsAny(col, "0123456789") {
+			b.fail(fmt.Errorf("Invalid comparison column: %s", col))
+			return removed
+		}
+		cgr := &sheets.GridRange{SheetId: gr.SheetId}
+		if b.fail(parseCells(col, cgr)) {
+			return removed
+		}
+		dr, err := dimensionRange(cgr)
+		if b.fail(err) {
+			return removed
+		}
+		req.ComparisonColumns = append(req.ComparisonColumns, dr)
+	}
+	b.add(&sheets.Request{DeleteDuplicates: req}, func(resp *sheets.Response) {
+		*removed = resp.DeleteDuplicates.DuplicatesRemovedCount
+	})
+	return removed
+}
+
+// TrimWhitespace removes leading and trailing whitespace from every cell in
+// 'a1Range' and collapses runs of whitespace inside them to a single space.
+// The result is the number of cells changed.
+func (b *Batch) TrimWhitespace(a1Range string) *int64 {
+	changed := new(int64)
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return changed
+	}
+	b.add(&sheets.Request{
+		TrimWhitespace: &sheets.TrimWhitespaceRequest{Range: gr},
+	}, func(resp *sheets.Response) {
+		*changed = resp.TrimWhitespace.CellsChangedCount
+	})
+	return changed
+}
+
+// TextToColumns splits the text of each cell in the single column 'a1Range'
+// into the columns to its right (overwriting them).
+// 'delimiter' is COMMA, SEMICOLON, PERIOD, SPACE or AUTODETECT, or any other
+// string to split on it.
+func (b *Batch) TextToColumns(a1Range, delimiter string) *Batch {
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return b
+	}
+	req := &sheets.TextToColumnsRequest{Source: gr, DelimiterType: strings.ToUpper(delimiter)}
+	if _, named := delimiterStrings[req.DelimiterType]; !named {
+		req.DelimiterType = "CUSTOM"
+		req.Delimiter = delimiter
+	}
+	b.add(&sheets.Request{TextToColumns: req}, nil)
+	return b
+}
+
+// DeleteDuplicates removes rows in 'a1Range' of the spreadsheet doc
+// identified by 'id' which duplicate an earlier row and returns the number of
+// rows removed (see Batch.DeleteDuplicates)
+func (svc *Service) DeleteDuplicates(id, a1Range string, columns ...string) (int64, error) {
+	b := svc.NewBatch(id)
+	removed := b.DeleteDuplicates(a1Range, columns...)
+	err := b.Do()
+	return *removed, err
+}
+
+// TrimWhitespace trims the whitespace of every cell in 'a1Range' of the
+// spreadsheet doc identified by 'id' and returns the number of cells changed
+// (see Batch.TrimWhitespace)
+func (svc *Service) TrimWhitespace(id, a1Range string) (int64, error) {
+	b := svc.NewBatch(id)
+	changed := b.TrimWhitespace(a1Range)
+	err := b.Do()
+	return *changed, err
+}
+
+// TextToColumns splits the text of each cell in the single column 'a1Range'
+// of the spreadsheet doc identified by 'id' into columns on 'delimiter' (see
+// Batch.TextToColumns).
+// The API does not report what changed, so the number of cells in the column
+// which contain the delimiter (read before splitting) is returned. For
+// AUTODETECT that is any cell containing a comma, semicolon, period or
+// space, so it may count some cells which are not split.
+func (svc *Service) TextToColumns(id, a1Range, delimiter string) (int64, error) {
+	rows, err := svc.GetRangeFormatted(id, a1Range)
+	if err != nil {
+		return 0, err
+	}
+	var cells int64
+	for _, row := range rows {
+		for _, val := range row {
+			if splits(val, delimiter) {
+				cells++
+			}
+		}
+	}
+	return cells, svc.NewBatch(id).TextToColumns(a1Range, delimiter).Do()
+}
@@ -0,0 +1,26 @@
+package gsheets
+
+import "testing"
+
+func TestSplits(t *testing.T) {
+	tests := []struct {
+		text, delimiter string
+		want            bool
+	}{
+		{"a,b", "comma", true},
+		{"a;b", "comma", false},
+		{"a;b", "SEMICOLON", true},
+		{"1.5", "period", true},
+		{"a b", "space", true},
+		{"ab", "autodetect", false},
+		{"a;b", "autodetect", true},
+		{"a|b", "|", true},
+		{"a,b", "|", false},
+		{"", "autodetect", false},
+	}
+	for _, tt := range tests {
+		if got := splits(tt.text, tt.delimiter); got != tt.want {
+			t.Errorf("splits(%q, %q) = %v, want %v", tt.text, tt.delimiter, got, tt.want)
+		}
+	}
+}
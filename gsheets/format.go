@@ -0,0 +1,276 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Border describes the style of one edge of a cell.
+// Style is one of DOTTED, DASHED, SOLID, SOLID_MEDIUM, SOLID_THICK, DOUBLE or
+// NONE. Color is a hex string such as "#000000".
+type Border struct {
+	Style string `yaml:"style"`
+	Color string `yaml:"color,omitempty"`
+}
+
+// CellFormat describes formatting to apply to a range of cells. Only the
+// fields which are set are changed; all other formatting in the range is left
+// as it is.
+// Colors are given as hex strings ("#RRGGBB").
+// see: https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/cells#CellFormat
+type CellFormat struct {
+	// NumberFormat is a pattern such as "$#,##0.00", "0.00%" or "yyyy-mm-dd"
+	// https://developers.google.com/sheets/api/guides/formats
+	NumberFormat string `yaml:"numberFormat,omitempty"`
+	// NumberType is one of TEXT, NUMBER, PERCENT, CURRENCY, DATE, TIME,
+	// DATE_TIME or SCIENTIFIC. If it is not set it is DATE, TIME or
+	// DATE_TIME when NumberFormat has date or time tokens, and NUMBER
+	// otherwise.
+	NumberType string `yaml:"numberType,omitempty"`
+
+	Bold   *bool `yaml:"bold,omitempty"`
+	Italic *bool `yaml:"italic,omitempty"`
+
+	ForegroundColor string `yaml:"foregroundColor,omitempty"`
+	BackgroundColor string `yaml:"backgroundColor,omitempty"`
+
+	// HorizontalAlignment is one of LEFT, CENTER or RIGHT
+	HorizontalAlignment string `yaml:"horizontalAlignment,omitempty"`
+	// VerticalAlignment is one of TOP, MIDDLE or BOTTOM
+	VerticalAlignment string `yaml:"verticalAlignment,omitempty"`
+	// WrapStrategy is one of OVERFLOW_CELL, CLIP or WRAP
+	WrapStrategy string `yaml:"wrapStrategy,omitempty"`
+
+	// Borders applies the same border to every edge of every cell in the
+	// range. Set the individual edges to override it.
+	Borders *Border `yaml:"borders,omitempty"`
+	Top     *Border `yaml:"top,omitempty"`
+	Bottom  *Border `yaml:"bottom,omitempty"`
+	Left    *Border `yaml:"left,omitempty"`
+	Right   *Border `yaml:"right,omitempty"`
+}
+
+// parseColor converts a hex string ("#RRGGBB" or "RRGGBB") to a sheets.Color
+func parseColor(hex string) (*sheets.Color, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("Invalid color: %s (expected #RRGGBB)", hex)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid color: %s (expected #RRGGBB)", hex)
+	}
+	return &sheets.Color{
+		Red:   float64(rgb>>16&0xff) / 255,
+		Green: float64(rgb>>8&0xff) / 255,
+		Blue:  float64(rgb&0xff) / 255,
+	}, nil
+}
+
+// colorHex converts a sheets.Color to a hex string ("#RRGGBB")
+func colorHex(c *sheets.Color) string {
+	if c == nil {
+		return ""
+	}
+	channel := func(v float64) int {
+		return int(v*255 + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(c.Red), channel(c.Green), channel(c.Blue))
+}
+
+// colorStyleName converts a sheets.ColorStyle to a hex string ("#RRGGBB"),
+// or to the name of its theme color (eg "ACCENT1") if it has no RGB color
+func colorStyleName(cs *sheets.ColorStyle) string {
+	if cs == nil {
+		return ""
+	}
+	if cs.RgbColor == nil {
+		return cs.ThemeColor
+	}
+	return colorHex(cs.RgbColor)
+}
+
+// toSheets converts a Border to its sheets API representation
+func (b *Border) toSheets() (*sheets.Border, error) {
+	border := &sheets.Border{Style: strings.ToUpper(b.Style)}
+	if b.Color != "" {
+		color, err := parseColor(b.Color)
+		if err != nil {
+			return nil, err
+		}
+		border.Color = color
+	}
+	return border, nil
+}
+
+// numberType guesses the type of the number format 'pattern' from its date
+// (d, y and m for months) and time (h, s, m next to them and AM/PM) tokens:
+// DATE, TIME, DATE_TIME or, if it has neither, NUMBER
+func numberType(pattern string) string {
+	// drop quoted text, escaped characters and bracketed sections (other
+	// than elapsed time, eg [h]), which are not tokens
+	var tokens strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '"':
+			if end := strings.IndexByte(pattern[i+1:], '"'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(pattern)
+			}
+		case '\\':
+			i++
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				end = len(pattern) - i
+			}
+			if section := strings.ToLower(pattern[i+1 : i+end]); strings.Trim(section, "hms") == "" {
+				tokens.WriteString(section)
+			}
+			i += end
+		default:
+			tokens.WriteByte(c)
+		}
+	}
+	t := strings.ToLower(tokens.String())
+
+	hasTime := strings.ContainsAny(t, "hs") || strings.Contains(t, "am/pm") || strings.Contains(t, "a/p")
+	// m is minutes next to hours or seconds, and months otherwise
+	hasDate := strings.ContainsAny(t, "dy") || (strings.Contains(t, "m") && !hasTime)
+	switch {
+	case hasDate && hasTime:
+		return "DATE_TIME"
+	case hasDate:
+		return "DATE"
+	case hasTime:
+		return "TIME"
+	}
+	return "NUMBER"
+}
+
+// toSheets converts the CellFormat into a sheets.CellFormat along with the
+// field mask naming every field that was set
+func (f *CellFormat) toSheets() (*sheets.CellFormat, []string, error) {
+	var fields []string
+	cf := &sheets.CellFormat{}
+
+	if f.NumberFormat != "" || f.NumberType != "" {
+		numType := strings.ToUpper(f.NumberType)
+		if numType == "" {
+			numType = numberType(f.NumberFormat)
+		}
+		cf.NumberFormat = &sheets.NumberFormat{
+			Type:    numType,
+			Pattern: f.NumberFormat,
+		}
+		fields = append(fields, "numberFormat")
+	}
+
+	if f.Bold != nil || f.Italic != nil || f.ForegroundColor != "" {
+		cf.TextFormat = &sheets.TextFormat{}
+	}
+	if f.Bold != nil {
+		cf.TextFormat.Bold = *f.Bold
+		cf.TextFormat.ForceSendFields = append(cf.TextFormat.ForceSendFields, "Bold")
+		fields = append(fields, "textFormat.bold")
+	}
+	if f.Italic != nil {
+		cf.TextFormat.Italic = *f.Italic
+		cf.TextFormat.ForceSendFields = append(cf.TextFormat.ForceSendFields, "Italic")
+		fields = append(fields, "textFormat.italic")
+	}
+	if f.ForegroundColor != "" {
+		color, err := parseColor(f.ForegroundColor)
+		if err != nil {
+			return nil, nil, err
+		}
+		cf.TextFormat.ForegroundColor = color
+		fields = append(fields, "textFormat.foregroundColor")
+	}
+	if f.BackgroundColor != "" {
+		color, err := parseColor(f.BackgroundColor)
+		if err != nil {
+			return nil, nil, err
+		}
+		cf.BackgroundColor = color
+		fields = append(fields, "backgroundColor")
+	}
+
+	if f.HorizontalAlignment != "" {
+		cf.HorizontalAlignment = strings.ToUpper(f.HorizontalAlignment)
+		fields = append(fields, "horizontalAlignment")
+	}
+	if f.VerticalAlignment != "" {
+		cf.VerticalAlignment = strings.ToUpper(f.VerticalAlignment)
+		fields = append(fields, "verticalAlignment")
+	}
+	if f.WrapStrategy != "" {
+		cf.WrapStrategy = strings.ToUpper(f.WrapStrategy)
+		fields = append(fields, "wrapStrategy")
+	}
+
+	edges := []struct {
+		name   string
+		border *Border
+		set    func(*sheets.Borders, *sheets.Border)
+	}{
+		{"top", f.Top, func(b *sheets.Borders, v *sheets.Border) { b.Top = v }},
+		{"bottom", f.Bottom, func(b *sheets.Borders, v *sheets.Border) { b.Bottom = v }},
+		{"left", f.Left, func(b *sheets.Borders, v *sheets.Border) { b.Left = v }},
+		{"right", f.Right, func(b *sheets.Borders, v *sheets.Border) { b.Right = v }},
+	}
+	for _, edge := range edges {
+		border := edge.border
+		if border == nil {
+			border = f.Borders
+		}
+		if border == nil {
+			continue
+		}
+		b, err := border.toSheets()
+		if err != nil {
+			return nil, nil, err
+		}
+		if cf.Borders == nil {
+			cf.Borders = &sheets.Borders{}
+		}
+		edge.set(cf.Borders, b)
+		fields = append(fields, "borders."+edge.name)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil, errors.New("No formatting given")
+	}
+	return cf, fields, nil
+}
+
+// formatRequest builds a repeatCell request which applies 'format' to every
+// cell in 'gr'
+func formatRequest(gr *sheets.GridRange, format *CellFormat) (*sheets.Request, error) {
+	cf, fields, err := format.toSheets()
+	if err != nil {
+		return nil, err
+	}
+	for i, field := range fields {
+		fields[i] = "userEnteredFormat." + field
+	}
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range:  gr,
+			Cell:   &sheets.CellData{UserEnteredFormat: cf},
+			Fields: strings.Join(fields, ","),
+		},
+	}, nil
+}
+
+// FormatRange applies 'format' to every cell in 'a1Range' of the spreadsheet
+// doc identified by 'id'.
+// Only the formatting fields set in 'format' are changed.
+func (svc *Service) FormatRange(id, a1Range string, format *CellFormat) error {
+	return svc.NewBatch(id).Format(a1Range, format).Do()
+}
@@ -0,0 +1,50 @@
+package gsheets
+
+import (
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestNumberType(t *testing.T) {
+	tests := []struct {
+		pattern, want string
+	}{
+		{"", "NUMBER"},
+		{"$#,##0.00", "NUMBER"},
+		{"0.00%", "NUMBER"},
+		{"0.00E+00", "NUMBER"},
+		{`#,##0 "days"`, "NUMBER"},
+		{`0.0\s`, "NUMBER"},
+		{"[Red]#,##0", "NUMBER"},
+		{"yyyy-mm-dd", "DATE"},
+		{"mmm d", "DATE"},
+		{"MM/DD/YYYY", "DATE"},
+		{"hh:mm", "TIME"},
+		{"h:mm am/pm", "TIME"},
+		{"[h]:mm:ss", "TIME"},
+		{"yyyy-mm-dd hh:mm:ss", "DATE_TIME"},
+		{"d mmm h AM/PM", "DATE_TIME"},
+	}
+	for _, tt := range tests {
+		if got := numberType(tt.pattern); got != tt.want {
+			t.Errorf("numberType(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestColorStyleName(t *testing.T) {
+	tests := []struct {
+		cs   *sheets.ColorStyle
+		want string
+	}{
+		{nil, ""},
+		{&sheets.ColorStyle{RgbColor: &sheets.Color{Red: 1}}, "#ff0000"},
+		{&sheets.ColorStyle{ThemeColor: "ACCENT1"}, "ACCENT1"},
+	}
+	for _, tt := range tests {
+		if got := colorStyleName(tt.cs); got != tt.want {
+			t.Errorf("colorStyleName(%+v) = %q, want %q", tt.cs, got, tt.want)
+		}
+	}
+}
@@ -102,6 +102,22 @@ func TestSheetIntegration(t *testing.T) {
 	if resp.UpdatedCells != 12 {
 		t.Fatal("Unexpected number of cells updated")
 	}
+	nr, err := svcSheet.AddNamedRange(testfile.Id, "header", "TEST!A1:C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nr.Range != "'TEST'!A1:C1" {
+		t.Fatalf("Unexpected named range bounds: %s", nr.Range)
+	}
+
+	bold := true
+	err = svcSheet.FormatRange(testfile.Id, "header", &CellFormat{
+		Bold:            &bold,
+		BackgroundColor: "#dddddd",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 	vals, err := svcSheet.GetRangeCSV(testfile.Id, "TEST")
 	if err != nil {
 		t.Fatal(err)
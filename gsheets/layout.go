@@ -0,0 +1,216 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// dimensionRange converts 'gr' to a DimensionRange. 'gr' must cover whole rows
+// ("2:5") or whole columns ("A:C").
+func dimensionRange(gr *sheets.GridRange) (*sheets.DimensionRange, error) {
+	rows := gr.StartRowIndex > 0 || gr.EndRowIndex > 0
+	cols := gr.StartColumnIndex > 0 || gr.EndColumnIndex > 0
+	switch {
+	case cols && !rows:
+		return &sheets.DimensionRange{
+			SheetId:    gr.SheetId,
+			Dimension:  "COLUMNS",
+			StartIndex: gr.StartColumnIndex,
+			EndIndex:   gr.EndColumnIndex,
+		}, nil
+	case rows && !cols:
+		return &sheets.DimensionRange{
+			SheetId:    gr.SheetId,
+			Dimension:  "ROWS",
+			StartIndex: gr.StartRowIndex,
+			EndIndex:   gr.EndRowIndex,
+		}, nil
+	}
+	return nil, errors.New("Range must be whole rows (eg '2:5') or whole columns (eg 'A:C')")
+}
+
+// DimensionRange converts 'a1Range' into a DimensionRange on the spreadsheet
+// doc identified by 'id'.
+// 'a1Range' must cover whole rows ("Sheet1!2:5") or whole columns
+// ("Sheet1!A:C").
+func (svc *Service) DimensionRange(id, a1Range string) (*sheets.DimensionRange, error) {
+	gr, err := svc.GridRange(id, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	return dimensionRange(gr)
+}
+
+// freezeRequest builds a request which freezes the first 'rows' rows and
+// 'cols' columns of the sheet 'sheetId'. A negative count is left unchanged.
+func freezeRequest(sheetId, rows, cols int64) (*sheets.Request, error) {
+	grid := &sheets.GridProperties{}
+	var fields []string
+	if rows >= 0 {
+		grid.FrozenRowCount = rows
+		grid.ForceSendFields = append(grid.ForceSendFields, "FrozenRowCount")
+		fields = append(fields, "gridProperties.frozenRowCount")
+	}
+	if cols >= 0 {
+		grid.FrozenColumnCount = cols
+		grid.ForceSendFields = append(grid.ForceSendFields, "FrozenColumnCount")
+		fields = append(fields, "gridProperties.frozenColumnCount")
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("Nothing to freeze")
+	}
+	return &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:        sheetId,
+				GridProperties: grid,
+			},
+			Fields: strings.Join(fields, ","),
+		},
+	}, nil
+}
+
+// dimensionPropertiesRequest builds a request which updates the 'fields' of
+// 'dr' to 'props'
+func dimensionPropertiesRequest(dr *sheets.DimensionRange, props *sheets.DimensionProperties, fields string) *sheets.Request {
+	return &sheets.Request{
+		UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+			Range:      dr,
+			Properties: props,
+			Fields:     fields,
+		},
+	}
+}
+
+// Freeze freezes the first 'rows' rows and 'cols' columns of the sheet titled
+// 'title' in the spreadsheet doc identified by 'id'.
+// Use 0 to unfreeze, or a negative count to leave the rows or columns as they
+// are.
+func (svc *Service) Freeze(id, title string, rows, cols int64) error {
+	sheetId, err := svc.SheetFromTitle(id, title)
+	if err != nil {
+		return err
+	}
+	if sheetId == nil {
+		return fmt.Errorf("No sheet titled %s found", title)
+	}
+	req, err := freezeRequest(*sheetId, rows, cols)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, req)
+	return err
+}
+
+// Resize sets the height of the rows or the width of the columns in
+// 'a1Range' to 'pixels'.
+func (svc *Service) Resize(id, a1Range string, pixels int64) error {
+	dr, err := svc.DimensionRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, dimensionPropertiesRequest(dr,
+		&sheets.DimensionProperties{PixelSize: pixels}, "pixelSize"))
+	return err
+}
+
+// AutoResize resizes the rows or columns in 'a1Range' to fit their contents
+func (svc *Service) AutoResize(id, a1Range string) error {
+	dr, err := svc.DimensionRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+			Dimensions: dr,
+		},
+	})
+	return err
+}
+
+// Hide hides (or unhides if 'hidden' is false) the rows or columns in
+// 'a1Range'
+func (svc *Service) Hide(id, a1Range string, hidden bool) error {
+	dr, err := svc.DimensionRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, dimensionPropertiesRequest(dr,
+		&sheets.DimensionProperties{
+			HiddenByUser:    hidden,
+			ForceSendFields: []string{"HiddenByUser"},
+		}, "hiddenByUser"))
+	return err
+}
+
+// Group groups the rows or columns in 'a1Range' so they can be collapsed.
+// Grouping a range inside an existing group creates a nested group.
+func (svc *Service) Group(id, a1Range string) error {
+	dr, err := svc.DimensionRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		AddDimensionGroup: &sheets.AddDimensionGroupRequest{Range: dr},
+	})
+	return err
+}
+
+// Ungroup removes one level of grouping from the rows or columns in
+// 'a1Range'
+func (svc *Service) Ungroup(id, a1Range string) error {
+	dr, err := svc.DimensionRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		DeleteDimensionGroup: &sheets.DeleteDimensionGroupRequest{Range: dr},
+	})
+	return err
+}
+
+// CollapseGroup collapses (or expands if 'collapsed' is false) the row or
+// column group covering exactly 'a1Range'
+func (svc *Service) CollapseGroup(id, a1Range string, collapsed bool) error {
+	dr, err := svc.DimensionRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+
+	ss, err := svc.sheet.Get(id).Fields("sheets(properties.sheetId,rowGroups,columnGroups)").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	var group *sheets.DimensionGroup
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties.SheetId != dr.SheetId {
+			continue
+		}
+		groups := sheet.RowGroups
+		if dr.Dimension == "COLUMNS" {
+			groups = sheet.ColumnGroups
+		}
+		for _, g := range groups {
+			if g.Range.StartIndex == dr.StartIndex && g.Range.EndIndex == dr.EndIndex {
+				group = g
+				break
+			}
+		}
+	}
+	if group == nil {
+		return fmt.Errorf("No group found covering %s", a1Range)
+	}
+
+	group.Collapsed = collapsed
+	group.ForceSendFields = []string{"Collapsed"}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		UpdateDimensionGroup: &sheets.UpdateDimensionGroupRequest{
+			DimensionGroup: group,
+			Fields:         "collapsed",
+		},
+	})
+	return err
+}
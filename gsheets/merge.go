@@ -0,0 +1,83 @@
+package gsheets
+
+import (
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Merge types for MergeCells
+const (
+	MergeAll     = "MERGE_ALL"     // merge the range into a single cell
+	MergeColumns = "MERGE_COLUMNS" // merge each column of the range
+	MergeRows    = "MERGE_ROWS"    // merge each row of the range
+)
+
+// MergeCells merges the cells in 'a1Range' of the spreadsheet doc identified
+// by 'id'.
+// 'mergeType' is one of MergeAll, MergeColumns or MergeRows (defaults to
+// MergeAll if empty).
+func (svc *Service) MergeCells(id, a1Range, mergeType string) error {
+	gr, err := svc.GridRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	if mergeType == "" {
+		mergeType = MergeAll
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		MergeCells: &sheets.MergeCellsRequest{
+			Range:     gr,
+			MergeType: strings.ToUpper(mergeType),
+		},
+	})
+	return err
+}
+
+// UnmergeCells unmerges any merged cells in 'a1Range' of the spreadsheet doc
+// identified by 'id'
+func (svc *Service) UnmergeCells(id, a1Range string) error {
+	gr, err := svc.GridRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		UnmergeCells: &sheets.UnmergeCellsRequest{Range: gr},
+	})
+	return err
+}
+
+// overlaps reports whether the bounds of 'a' and 'b' intersect (unset end
+// indexes are unbounded)
+func overlaps(a, b *sheets.GridRange) bool {
+	before := func(end, start int64) bool {
+		return end > 0 && end <= start
+	}
+	return a.SheetId == b.SheetId &&
+		!before(a.EndRowIndex, b.StartRowIndex) && !before(b.EndRowIndex, a.StartRowIndex) &&
+		!before(a.EndColumnIndex, b.StartColumnIndex) && !before(b.EndColumnIndex, a.StartColumnIndex)
+}
+
+// MergedRanges returns the merged cells which overlap 'a1Range' in the
+// spreadsheet doc identified by 'id', each in A1 notation.
+// Only the top-left cell of a merged range holds a value, so this can be used
+// to tell a merged cell apart from one which is really empty.
+func (svc *Service) MergedRanges(id, a1Range string) ([]string, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets(properties,merges),namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	gr, err := gridRange(ss, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	var merges []string
+	for _, sheet := range ss.Sheets {
+		for _, merge := range sheet.Merges {
+			if overlaps(gr, merge) {
+				merges = append(merges, A1FromGridRange(sheet.Properties.Title, merge))
+			}
+		}
+	}
+	return merges, nil
+}
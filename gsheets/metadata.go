@@ -0,0 +1,213 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Metadata is a developer metadata key/value pair attached to a spreadsheet
+// doc, a sheet, or rows or columns of a sheet.
+// Developer metadata stays with what it is attached to when sheets are
+// renamed or rows and columns are moved, so it can be used to find them again
+// reliably.
+// Location is "spreadsheet", a quoted sheet title ("'Sheet1'") or rows or
+// columns in A1 notation ("'Sheet1'!3:3").
+type Metadata struct {
+	Id       int64  `json:"id"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Location string `json:"location"`
+	SheetId  int64  `json:"sheetId"`
+}
+
+// newMetadata resolves the location of 'dm' against the sheets of 'ss'
+func newMetadata(ss *sheets.Spreadsheet, dm *sheets.DeveloperMetadata) *Metadata {
+	md := &Metadata{
+		Id:    dm.MetadataId,
+		Key:   dm.MetadataKey,
+		Value: dm.MetadataValue,
+	}
+	loc := dm.Location
+	switch {
+	case loc == nil || loc.Spreadsheet:
+		md.Location = "spreadsheet"
+	case loc.DimensionRange != nil:
+		dr := loc.DimensionRange
+		md.SheetId = dr.SheetId
+		gr := &sheets.GridRange{StartRowIndex: dr.StartIndex, EndRowIndex: dr.EndIndex}
+		if dr.Dimension == "COLUMNS" {
+			gr = &sheets.GridRange{StartColumnIndex: dr.StartIndex, EndColumnIndex: dr.EndIndex}
+		}
+		md.Location = A1FromGridRange(sheetTitle(ss, dr.SheetId), gr)
+	default:
+		md.SheetId = loc.SheetId
+		md.Location = quoteTitle(sheetTitle(ss, loc.SheetId))
+	}
+	return md
+}
+
+// metadataLocation converts 'a1Range' to a DeveloperMetadataLocation.
+// An empty range is the whole spreadsheet, a sheet title is the sheet, and
+// whole rows or columns ("Sheet1!3:3", "Sheet1!C:C") are those rows or columns.
+func metadataLocation(ss *sheets.Spreadsheet, a1Range string) (*sheets.DeveloperMetadataLocation, error) {
+	if a1Range == "" {
+		return &sheets.DeveloperMetadataLocation{Spreadsheet: true}, nil
+	}
+	gr, err := gridRange(ss, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	if isWholeSheet(gr) {
+		return &sheets.DeveloperMetadataLocation{
+			SheetId:         gr.SheetId,
+			ForceSendFields: []string{"SheetId"},
+		}, nil
+	}
+	dr, err := dimensionRange(gr)
+	if err != nil {
+		return nil, err
+	}
+	return &sheets.DeveloperMetadataLocation{DimensionRange: dr}, nil
+}
+
+// metadataFilter returns a DataFilter which matches metadata with 'key' and
+// (if not empty) 'value'
+func metadataFilter(key, value string) *sheets.DataFilter {
+	return &sheets.DataFilter{
+		DeveloperMetadataLookup: &sheets.DeveloperMetadataLookup{
+			MetadataKey:   key,
+			MetadataValue: value,
+		},
+	}
+}
+
+// ParseMetadataSelector splits a "key=value" selector into its key and value.
+// A selector without '=' matches any value.
+func ParseMetadataSelector(selector string) (key, value string) {
+	key, value, _ = strings.Cut(selector, "=")
+	return key, value
+}
+
+// SetMetadata attaches the metadata 'key'='value' to 'a1Range' in the
+// spreadsheet doc identified by 'id' and returns the new metadata.
+// If 'a1Range' is empty the metadata is attached to the spreadsheet itself;
+// if it is a sheet title, to the sheet; otherwise it must be whole rows or
+// columns ("Sheet1!3:3", "Sheet1!C:C").
+func (svc *Service) SetMetadata(id, a1Range, key, value string) (*Metadata, error) {
+	if key == "" {
+		return nil, errors.New("metadata key cannot be empty")
+	}
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	loc, err := metadataLocation(ss, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := svc.batchUpdate(id, &sheets.Request{
+		CreateDeveloperMetadata: &sheets.CreateDeveloperMetadataRequest{
+			DeveloperMetadata: &sheets.DeveloperMetadata{
+				MetadataKey:   key,
+				MetadataValue: value,
+				Location:      loc,
+				Visibility:    "DOCUMENT",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newMetadata(ss, resp.Replies[0].CreateDeveloperMetadata.DeveloperMetadata), nil
+}
+
+// SearchMetadata returns all metadata with 'key' in the spreadsheet doc
+// identified by 'id'. If 'value' is not empty only metadata with that value is
+// returned.
+func (svc *Service) SearchMetadata(id, key, value string) ([]*Metadata, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := svc.metadata.Search(id, &sheets.SearchDeveloperMetadataRequest{
+		DataFilters: []*sheets.DataFilter{metadataFilter(key, value)},
+	}).Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	var found []*Metadata
+	for _, m := range resp.MatchedDeveloperMetadata {
+		found = append(found, newMetadata(ss, m.DeveloperMetadata))
+	}
+	return found, nil
+}
+
+// DeleteMetadata deletes all metadata with 'key' (and 'value' if it is not
+// empty) from the spreadsheet doc identified by 'id' and returns the number of
+// entries deleted.
+// The sheets, rows or columns the metadata was attached to are not changed.
+func (svc *Service) DeleteMetadata(id, key, value string) (int, error) {
+	resp, err := svc.batchUpdate(id, &sheets.Request{
+		DeleteDeveloperMetadata: &sheets.DeleteDeveloperMetadataRequest{
+			DataFilter: metadataFilter(key, value),
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(resp.Replies[0].DeleteDeveloperMetadata.DeletedDeveloperMetadata), nil
+}
+
+// RangeFromMetadata returns the location of the metadata 'key'='value' in the
+// spreadsheet doc identified by 'id' in A1 notation (see Metadata).
+// It is an error if there is not exactly one match or if the metadata is
+// attached to the spreadsheet itself.
+func (svc *Service) RangeFromMetadata(id, key, value string) (string, error) {
+	md, err := svc.SearchMetadata(id, key, value)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case len(md) == 0:
+		return "", fmt.Errorf("No metadata found for %s=%s", key, value)
+	case len(md) > 1:
+		return "", fmt.Errorf("%d locations found for metadata %s=%s", len(md), key, value)
+	case md[0].Location == "spreadsheet":
+		return "", fmt.Errorf("Metadata %s=%s is not attached to a sheet", key, value)
+	}
+	return md[0].Location, nil
+}
+
+// RelativeRange returns 'a1Range' resolved against 'location', a metadata
+// location returned by RangeFromMetadata. If the metadata is attached to a
+// whole sheet, 'a1Range' is a range on that sheet; if it is attached to rows
+// or columns, no 'a1Range' may be given. An empty 'a1Range' is the whole
+// location.
+func RelativeRange(location, a1Range string) (string, error) {
+	if a1Range == "" {
+		return location, nil
+	}
+	title, cells := splitA1(location)
+	if cells != "" {
+		return "", fmt.Errorf("A range cannot be given with metadata on rows or columns (%s)", location)
+	}
+	if strings.Contains(a1Range, "!") {
+		return "", fmt.Errorf("Range %s must be relative to the metadata's sheet, without a sheet title", a1Range)
+	}
+	return quoteTitle(title) + "!" + a1Range, nil
+}
+
+// SheetFromMetadata returns the title of the sheet which the metadata
+// 'key'='value' is attached to (or which contains the rows or columns it is
+// attached to)
+func (svc *Service) SheetFromMetadata(id, key, value string) (string, error) {
+	a1, err := svc.RangeFromMetadata(id, key, value)
+	if err != nil {
+		return "", err
+	}
+	title, _ := splitA1(a1)
+	return title, nil
+}
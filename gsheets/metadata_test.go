@@ -0,0 +1,30 @@
+package gsheets
+
+import "testing"
+
+func TestRelativeRange(t *testing.T) {
+	tests := []struct {
+		location, a1Range string
+		want              string
+		wantErr           bool
+	}{
+		{"'Sheet1'", "", "'Sheet1'", false},
+		{"'Sheet1'", "A1:C10", "'Sheet1'!A1:C10", false},
+		{"'a!b'", "B2", "'a!b'!B2", false},
+		{"'it''s'", "B2", "'it''s'!B2", false},
+		{"'Sheet1'!2:5", "", "'Sheet1'!2:5", false},
+		{"'Sheet1'!2:5", "A1:C10", "", true},
+		{"'Sheet1'!B:B", "A1", "", true},
+		{"'Sheet1'", "Sheet2!A1", "", true},
+	}
+	for _, tt := range tests {
+		got, err := RelativeRange(tt.location, tt.a1Range)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s with %q: got error %v", tt.location, tt.a1Range, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s with %q: got %q, want %q", tt.location, tt.a1Range, got, tt.want)
+		}
+	}
+}
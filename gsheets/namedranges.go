@@ -0,0 +1,152 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// NamedRange is a named range in a spreadsheet doc along with the title of
+// the sheet it is on and its bounds in A1 notation
+type NamedRange struct {
+	Id         string `json:"id" yaml:"-"`
+	Name       string `json:"name" yaml:"name"`
+	SheetId    int64  `json:"sheetId" yaml:"-"`
+	SheetTitle string `json:"sheetTitle" yaml:"-"`
+	Range      string `json:"range" yaml:"range"`
+}
+
+// newNamedRange resolves 'nr' against the sheets of 'ss'
+func newNamedRange(ss *sheets.Spreadsheet, nr *sheets.NamedRange) *NamedRange {
+	title := sheetTitle(ss, nr.Range.SheetId)
+	return &NamedRange{
+		Id:         nr.NamedRangeId,
+		Name:       nr.Name,
+		SheetId:    nr.Range.SheetId,
+		SheetTitle: title,
+		Range:      A1FromGridRange(title, nr.Range),
+	}
+}
+
+// NamedRanges returns all of the named ranges in the spreadsheet doc
+// identified by 'id'
+func (svc *Service) NamedRanges(id string) ([]*NamedRange, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	ranges := make([]*NamedRange, len(ss.NamedRanges))
+	for i, nr := range ss.NamedRanges {
+		ranges[i] = newNamedRange(ss, nr)
+	}
+	return ranges, nil
+}
+
+// namedRange finds the named range called 'name' in 'ss'
+func namedRange(ss *sheets.Spreadsheet, name string) (*sheets.NamedRange, error) {
+	for _, nr := range ss.NamedRanges {
+		if nr.Name == name {
+			return nr, nil
+		}
+	}
+	return nil, fmt.Errorf("No named range %s found", name)
+}
+
+// AddNamedRange creates a new range called 'name' covering 'a1Range' in the
+// spreadsheet doc identified by 'id'
+func (svc *Service) AddNamedRange(id, name, a1Range string) (*NamedRange, error) {
+	if name == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	gr, err := gridRange(ss, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				AddNamedRange: &sheets.AddNamedRangeRequest{
+					NamedRange: &sheets.NamedRange{
+						Name:  name,
+						Range: gr,
+					},
+				},
+			},
+		},
+	}).Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return newNamedRange(ss, resp.Replies[0].AddNamedRange.NamedRange), nil
+}
+
+// UpdateNamedRange renames the range called 'name' to 'newName' and/or
+// changes the cells it covers to 'a1Range' in the spreadsheet doc identified
+// by 'id'.
+// Empty 'newName' or 'a1Range' are left unchanged.
+func (svc *Service) UpdateNamedRange(id, name, newName, a1Range string) error {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	nr, err := namedRange(ss, name)
+	if err != nil {
+		return err
+	}
+
+	update := &sheets.UpdateNamedRangeRequest{
+		NamedRange: &sheets.NamedRange{NamedRangeId: nr.NamedRangeId},
+	}
+	var fields []string
+	if newName != "" {
+		update.NamedRange.Name = newName
+		fields = append(fields, "name")
+	}
+	if a1Range != "" {
+		gr, err := gridRange(ss, a1Range)
+		if err != nil {
+			return err
+		}
+		update.NamedRange.Range = gr
+		fields = append(fields, "range")
+	}
+	if len(fields) == 0 {
+		return errors.New("Nothing to update")
+	}
+	update.Fields = strings.Join(fields, ",")
+
+	_, err = svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{UpdateNamedRange: update}},
+	}).Context(svc.ctx).Do()
+	return err
+}
+
+// DeleteNamedRange deletes the range called 'name' from the spreadsheet doc
+// identified by 'id'.
+// The cells in the range are not changed.
+func (svc *Service) DeleteNamedRange(id, name string) error {
+	ss, err := svc.sheet.Get(id).Fields("namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	nr, err := namedRange(ss, name)
+	if err != nil {
+		return err
+	}
+	_, err = svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				DeleteNamedRange: &sheets.DeleteNamedRangeRequest{
+					NamedRangeId: nr.NamedRangeId,
+				},
+			},
+		},
+	}).Context(svc.ctx).Do()
+	return err
+}
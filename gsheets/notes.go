@@ -0,0 +1,176 @@
+package gsheets
+
+import (
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Hyperlink is a link to URI displayed as Text in a cell.
+// If Text is empty the URI is displayed.
+type Hyperlink struct {
+	URI  string
+	Text string
+}
+
+// CellNote is the note attached to a single cell (in A1 notation)
+type CellNote struct {
+	Cell string
+	Note string
+}
+
+// CellLink is the hyperlink displayed in a single cell (in A1 notation)
+type CellLink struct {
+	Cell string
+	Hyperlink
+}
+
+// cellRequest builds a request which sets 'fields' of the single cell at
+// 'gr' to 'cell'
+func cellRequest(gr *sheets.GridRange, cell *sheets.CellData, fields string) *sheets.Request {
+	return &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Start: &sheets.GridCoordinate{
+				SheetId:     gr.SheetId,
+				RowIndex:    gr.StartRowIndex,
+				ColumnIndex: gr.StartColumnIndex,
+			},
+			Rows:   []*sheets.RowData{{Values: []*sheets.CellData{cell}}},
+			Fields: fields,
+		},
+	}
+}
+
+// noteCell returns CellData with 'note'
+func noteCell(note string) *sheets.CellData {
+	return &sheets.CellData{Note: note}
+}
+
+// linkCell returns CellData which displays 'link'
+func linkCell(link Hyperlink) *sheets.CellData {
+	text := link.Text
+	if text == "" {
+		text = link.URI
+	}
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{StringValue: &text},
+		UserEnteredFormat: &sheets.CellFormat{
+			TextFormat: &sheets.TextFormat{
+				Link: &sheets.Link{Uri: link.URI},
+			},
+		},
+	}
+}
+
+// linkFields is the field mask for updating the cells returned by linkCell
+const linkFields = "userEnteredValue,userEnteredFormat.textFormat.link"
+
+// GetNotes returns the notes attached to cells in 'a1Range' of the
+// spreadsheet doc identified by 'id'.
+// Cells without a note are omitted.
+func (svc *Service) GetNotes(id, a1Range string) ([]CellNote, error) {
+	ss, err := svc.sheet.Get(id).
+		Ranges(a1Range).
+		IncludeGridData(true).
+		Fields("sheets(properties.title,data(startRow,startColumn,rowData.values.note))").
+		Context(svc.ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	var notes []CellNote
+	for _, sheet := range ss.Sheets {
+		for _, data := range sheet.Data {
+			for r, row := range data.RowData {
+				for c, cell := range row.Values {
+					if cell.Note == "" {
+						continue
+					}
+					a1 := fmt.Sprintf("%s!%s%d", quoteTitle(sheet.Properties.Title),
+						columnLetters(data.StartColumn+int64(c)), data.StartRow+int64(r)+1)
+					notes = append(notes, CellNote{Cell: a1, Note: cell.Note})
+				}
+			}
+		}
+	}
+	return notes, nil
+}
+
+// SetNote sets the note of every cell in 'a1Range' of the spreadsheet doc
+// identified by 'id' to 'note' (an empty note removes it)
+func (svc *Service) SetNote(id, a1Range, note string) error {
+	gr, err := svc.GridRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range:  gr,
+			Cell:   noteCell(note),
+			Fields: "note",
+		},
+	})
+	return err
+}
+
+// SetNotes sets the note of each cell in 'notes' (cells in A1 notation) in a
+// single update of the spreadsheet doc identified by 'id'
+func (svc *Service) SetNotes(id string, notes []CellNote) error {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	var reqs []*sheets.Request
+	for _, n := range notes {
+		gr, err := gridRange(ss, n.Cell)
+		if err != nil {
+			return err
+		}
+		reqs = append(reqs, cellRequest(gr, noteCell(n.Note), "note"))
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+	_, err = svc.batchUpdate(id, reqs...)
+	return err
+}
+
+// SetHyperlink sets every cell in 'a1Range' of the spreadsheet doc identified
+// by 'id' to display 'link'.
+// This replaces the value of the cells.
+func (svc *Service) SetHyperlink(id, a1Range string, link Hyperlink) error {
+	gr, err := svc.GridRange(id, a1Range)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range:  gr,
+			Cell:   linkCell(link),
+			Fields: linkFields,
+		},
+	})
+	return err
+}
+
+// SetHyperlinks sets each cell in 'links' (cells in A1 notation) to display
+// its hyperlink in a single update of the spreadsheet doc identified by 'id'
+func (svc *Service) SetHyperlinks(id string, links []CellLink) error {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	var reqs []*sheets.Request
+	for _, l := range links {
+		gr, err := gridRange(ss, l.Cell)
+		if err != nil {
+			return err
+		}
+		reqs = append(reqs, cellRequest(gr, linkCell(l.Hyperlink), linkFields))
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+	_, err = svc.batchUpdate(id, reqs...)
+	return err
+}
@@ -0,0 +1,143 @@
+package gsheets
+
+import (
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Paste types for CopyRange and MoveRange
+const (
+	PasteNormal            = "PASTE_NORMAL"                 // values, formulas, formats and merges
+	PasteValues            = "PASTE_VALUES"                 // values only, without formats, formulas or merges
+	PasteFormat            = "PASTE_FORMAT"                 // formats and data validation only
+	PasteNoBorders         = "PASTE_NO_BORDERS"             // like PasteNormal, but without borders
+	PasteFormula           = "PASTE_FORMULA"                // formulas only
+	PasteValidation        = "PASTE_DATA_VALIDATION"        // data validation only
+	PasteConditionalFormat = "PASTE_CONDITIONAL_FORMATTING" // conditional formatting rules only
+)
+
+// pasteTypeOrNormal returns 't' in upper case, or PasteNormal if it is empty
+func pasteTypeOrNormal(t string) string {
+	if t == "" {
+		return PasteNormal
+	}
+	return strings.ToUpper(t)
+}
+
+// CopyRange copies the cells in 'from' to 'to'.
+// If 'to' is a single cell it is the top-left corner of the pasted cells; if
+// it is larger than 'from' the copy is repeated to fill it.
+// 'pasteType' is one of the Paste constants (defaults to PasteNormal). If
+// 'transpose' is set, rows are pasted as columns and columns as rows.
+func (b *Batch) CopyRange(from, to, pasteType string, transpose bool) *Batch {
+	src, err := b.gridRange(from)
+	if b.fail(err) {
+		return b
+	}
+	dst, err := b.gridRange(to)
+	if b.fail(err) {
+		return b
+	}
+	orientation := "NORMAL"
+	if transpose {
+		orientation = "TRANSPOSE"
+	}
+	b.add(&sheets.Request{
+		CopyPaste: &sheets.CopyPasteRequest{
+			Source:           src,
+			Destination:      dst,
+			PasteType:        pasteTypeOrNormal(pasteType),
+			PasteOrientation: orientation,
+		},
+	}, nil)
+	return b
+}
+
+// MoveRange cuts the cells in 'from' and pastes them with their top-left
+// corner at the first cell of 'to'. Formulas which refer to the moved cells
+// are updated to follow them.
+// 'pasteType' is one of the Paste constants (defaults to PasteNormal).
+func (b *Batch) MoveRange(from, to, pasteType string) *Batch {
+	src, err := b.gridRange(from)
+	if b.fail(err) {
+		return b
+	}
+	dst, err := b.gridRange(to)
+	if b.fail(err) {
+		return b
+	}
+	b.add(&sheets.Request{
+		CutPaste: &sheets.CutPasteRequest{
+			Source: src,
+			Destination: &sheets.GridCoordinate{
+				SheetId:     dst.SheetId,
+				RowIndex:    dst.StartRowIndex,
+				ColumnIndex: dst.StartColumnIndex,
+			},
+			PasteType: pasteTypeOrNormal(pasteType),
+		},
+	}, nil)
+	return b
+}
+
+// AutoFill fills the empty cells of 'a1Range' by continuing the data (or
+// formulas) in its non-empty cells, as when dragging the fill handle in the
+// Sheets UI
+func (b *Batch) AutoFill(a1Range string) *Batch {
+	gr, err := b.gridRange(a1Range)
+	if b.fail(err) {
+		return b
+	}
+	b.add(&sheets.Request{
+		AutoFill: &sheets.AutoFillRequest{Range: gr},
+	}, nil)
+	return b
+}
+
+// AutoFillFrom extends the data (or formulas) in 'source' by 'length' rows
+// (if 'dimension' is "ROWS") or columns (if it is "COLUMNS"). A positive
+// length fills down or right and a negative length fills up or left.
+// For example, to copy the formulas in row 2 down to the next 100 rows:
+//
+//	b.AutoFillFrom("Sheet1!E2:F2", "ROWS", 100)
+func (b *Batch) AutoFillFrom(source, dimension string, length int64) *Batch {
+	gr, err := b.gridRange(source)
+	if b.fail(err) {
+		return b
+	}
+	b.add(&sheets.Request{
+		AutoFill: &sheets.AutoFillRequest{
+			SourceAndDestination: &sheets.SourceAndDestination{
+				Source:     gr,
+				Dimension:  strings.ToUpper(dimension),
+				FillLength: length,
+			},
+		},
+	}, nil)
+	return b
+}
+
+// CopyRange copies the cells in 'from' to 'to' in the spreadsheet doc
+// identified by 'id' (see Batch.CopyRange)
+func (svc *Service) CopyRange(id, from, to, pasteType string, transpose bool) error {
+	return svc.NewBatch(id).CopyRange(from, to, pasteType, transpose).Do()
+}
+
+// MoveRange moves the cells in 'from' to 'to' in the spreadsheet doc
+// identified by 'id' (see Batch.MoveRange)
+func (svc *Service) MoveRange(id, from, to, pasteType string) error {
+	return svc.NewBatch(id).MoveRange(from, to, pasteType).Do()
+}
+
+// AutoFill fills the empty cells of 'a1Range' in the spreadsheet doc
+// identified by 'id' from its non-empty cells (see Batch.AutoFill)
+func (svc *Service) AutoFill(id, a1Range string) error {
+	return svc.NewBatch(id).AutoFill(a1Range).Do()
+}
+
+// AutoFillFrom extends 'source' by 'length' rows or columns in the
+// spreadsheet doc identified by 'id' (see Batch.AutoFillFrom)
+func (svc *Service) AutoFillFrom(id, source, dimension string, length int64) error {
+	return svc.NewBatch(id).AutoFillFrom(source, dimension, length).Do()
+}
@@ -0,0 +1,223 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// PivotField identifies a column of a pivot table's source range, either by
+// its column letter on the sheet (Column: "C") or by the header in the first
+// row of the source range (Field: "Revenue").
+type PivotField struct {
+	Column string `yaml:"column,omitempty" json:"column,omitempty"`
+	Field  string `yaml:"field,omitempty" json:"field,omitempty"`
+}
+
+// PivotGroup is a row or column grouping of a pivot table.
+// Order is ASCENDING (default) or DESCENDING. Set Totals to show a total for
+// each group.
+type PivotGroup struct {
+	PivotField `yaml:",inline"`
+	Order      string `yaml:"order,omitempty" json:"order,omitempty"`
+	Totals     bool   `yaml:"totals,omitempty" json:"totals,omitempty"`
+}
+
+// PivotValue is a summarized value of a pivot table.
+// Summarize is a function such as SUM (default), COUNT, COUNTA, AVERAGE, MAX
+// or MIN. Name is an optional label for the value.
+type PivotValue struct {
+	PivotField `yaml:",inline"`
+	Summarize  string `yaml:"summarize,omitempty" json:"summarize,omitempty"`
+	Name       string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+// PivotFilter limits a pivot table to rows whose value in the field is one of
+// Values
+type PivotFilter struct {
+	PivotField `yaml:",inline"`
+	Values     []string `yaml:"values" json:"values"`
+}
+
+// PivotSpec describes a pivot table over the Source range (which must include
+// a header row) placed with its top-left corner at the Anchor cell (eg
+// "Report!A1").
+// The yaml/json tags allow pivot specs to be kept in files.
+type PivotSpec struct {
+	Source  string        `yaml:"source" json:"source"`
+	Anchor  string        `yaml:"anchor" json:"anchor"`
+	Rows    []PivotGroup  `yaml:"rows,omitempty" json:"rows,omitempty"`
+	Columns []PivotGroup  `yaml:"columns,omitempty" json:"columns,omitempty"`
+	Values  []PivotValue  `yaml:"values,omitempty" json:"values,omitempty"`
+	Filters []PivotFilter `yaml:"filters,omitempty" json:"filters,omitempty"`
+}
+
+// offset returns the column offset of 'f' from the start of 'gr', looking up
+// field names in 'headers'
+func (f *PivotField) offset(gr *sheets.GridRange, headers []string) (int64, error) {
+	var off int64
+	switch {
+	case f.Column != "":
+		if !a1Column.MatchString(f.Column) {
+			return 0, fmt.Errorf("Invalid pivot column: %s", f.Column)
+		}
+		off = columnIndex(f.Column) - gr.StartColumnIndex
+	case f.Field != "":
+		off = -1
+		for i, h := range headers {
+			if h == f.Field {
+				off = int64(i)
+				break
+			}
+		}
+		if off < 0 {
+			return 0, fmt.Errorf("No column with header %s found in pivot source", f.Field)
+		}
+	default:
+		return 0, errors.New("Pivot field needs a column or field")
+	}
+	if off < 0 || (gr.EndColumnIndex > 0 && gr.StartColumnIndex+off >= gr.EndColumnIndex) {
+		return 0, fmt.Errorf("Pivot column %s%s is outside of the source range", f.Column, f.Field)
+	}
+	return off, nil
+}
+
+// usesFieldNames reports whether any part of the spec refers to a column by
+// its header
+func (spec *PivotSpec) usesFieldNames() bool {
+	for _, g := range append(spec.Rows, spec.Columns...) {
+		if g.Field != "" {
+			return true
+		}
+	}
+	for _, v := range spec.Values {
+		if v.Field != "" {
+			return true
+		}
+	}
+	for _, f := range spec.Filters {
+		if f.Field != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// toSheets converts the spec into a sheets.PivotTable over 'gr'
+func (spec *PivotSpec) toSheets(gr *sheets.GridRange, headers []string) (*sheets.PivotTable, error) {
+	pt := &sheets.PivotTable{Source: gr}
+
+	groups := func(specs []PivotGroup) ([]*sheets.PivotGroup, error) {
+		var groups []*sheets.PivotGroup
+		for _, g := range specs {
+			off, err := g.offset(gr, headers)
+			if err != nil {
+				return nil, err
+			}
+			order := strings.ToUpper(g.Order)
+			if order == "" {
+				order = "ASCENDING"
+			}
+			groups = append(groups, &sheets.PivotGroup{
+				SourceColumnOffset: off,
+				SortOrder:          order,
+				ShowTotals:         g.Totals,
+				ForceSendFields:    []string{"SourceColumnOffset", "ShowTotals"},
+			})
+		}
+		return groups, nil
+	}
+	var err error
+	if pt.Rows, err = groups(spec.Rows); err != nil {
+		return nil, err
+	}
+	if pt.Columns, err = groups(spec.Columns); err != nil {
+		return nil, err
+	}
+
+	for _, v := range spec.Values {
+		off, err := v.offset(gr, headers)
+		if err != nil {
+			return nil, err
+		}
+		fn := strings.ToUpper(v.Summarize)
+		if fn == "" {
+			fn = "SUM"
+		}
+		pt.Values = append(pt.Values, &sheets.PivotValue{
+			SourceColumnOffset: off,
+			SummarizeFunction:  fn,
+			Name:               v.Name,
+			ForceSendFields:    []string{"SourceColumnOffset"},
+		})
+	}
+
+	for _, f := range spec.Filters {
+		off, err := f.offset(gr, headers)
+		if err != nil {
+			return nil, err
+		}
+		pt.FilterSpecs = append(pt.FilterSpecs, &sheets.PivotFilterSpec{
+			ColumnOffsetIndex: off,
+			FilterCriteria:    &sheets.PivotFilterCriteria{VisibleValues: f.Values},
+			ForceSendFields:   []string{"ColumnOffsetIndex"},
+		})
+	}
+	return pt, nil
+}
+
+// CreatePivot creates the pivot table described by 'spec' in the spreadsheet
+// doc identified by 'id'.
+// Any pivot table already at the anchor cell is replaced, so running this
+// again with the same spec recreates the pivot.
+func (svc *Service) CreatePivot(id string, spec *PivotSpec) error {
+	if spec.Anchor == "" {
+		return errors.New("Pivot anchor cannot be empty")
+	}
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	src, err := gridRange(ss, spec.Source)
+	if err != nil {
+		return err
+	}
+	anchor, err := gridRange(ss, spec.Anchor)
+	if err != nil {
+		return err
+	}
+
+	var headers []string
+	if spec.usesFieldNames() {
+		header := *src
+		header.EndRowIndex = header.StartRowIndex + 1
+		rows, err := svc.GetRangeFormatted(id, A1FromGridRange(sheetTitle(ss, src.SheetId), &header))
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			headers = rows[0]
+		}
+	}
+
+	pt, err := spec.toSheets(src, headers)
+	if err != nil {
+		return err
+	}
+	_, err = svc.batchUpdate(id, &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Start: &sheets.GridCoordinate{
+				SheetId:     anchor.SheetId,
+				RowIndex:    anchor.StartRowIndex,
+				ColumnIndex: anchor.StartColumnIndex,
+			},
+			Rows: []*sheets.RowData{
+				{Values: []*sheets.CellData{{PivotTable: pt}}},
+			},
+			Fields: "pivotTable",
+		},
+	})
+	return err
+}
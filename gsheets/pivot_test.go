@@ -0,0 +1,39 @@
+package gsheets
+
+import (
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestPivotFieldOffset(t *testing.T) {
+	// the source range B1:E
+	gr := &sheets.GridRange{StartColumnIndex: 1, EndColumnIndex: 5}
+	headers := []string{"Date", "Region", "Product", "Revenue"}
+	tests := []struct {
+		field PivotField
+		want  int64
+		err   bool
+	}{
+		{PivotField{Column: "B"}, 0, false},
+		{PivotField{Column: "e"}, 3, false},
+		{PivotField{Field: "Product"}, 2, false},
+		{PivotField{Column: "A"}, 0, true},
+		{PivotField{Column: "F"}, 0, true},
+		{PivotField{Column: "C3"}, 0, true},
+		{PivotField{Column: "3"}, 0, true},
+		{PivotField{Column: "A:B"}, 0, true},
+		{PivotField{Field: "Cost"}, 0, true},
+		{PivotField{}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.offset(gr, headers)
+		if (err != nil) != tt.err {
+			t.Errorf("%+v: got error %v", tt.field, err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("%+v: got offset %d, want %d", tt.field, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package gsheets
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Protection describes a protected range or protected sheet.
+// When protecting a range, the Id, SheetId, SheetTitle, Range and WholeSheet
+// fields are ignored; they are filled in when listing protections.
+// If WarningOnly is set, anybody can edit the range after confirming a
+// warning (and the editor fields must be empty). Otherwise only the listed
+// Users, Groups (email addresses) and, if DomainUsersCanEdit is set, users in
+// the document's domain may edit it.
+// Unprotected lists A1 ranges within a protected sheet which remain editable.
+type Protection struct {
+	Id                 int64    `json:"id" yaml:"-"`
+	Description        string   `json:"description,omitempty" yaml:"description,omitempty"`
+	SheetId            int64    `json:"sheetId" yaml:"-"`
+	SheetTitle         string   `json:"sheetTitle" yaml:"-"`
+	Range              string   `json:"range" yaml:"range"`
+	WholeSheet         bool     `json:"wholeSheet" yaml:"-"`
+	WarningOnly        bool     `json:"warningOnly" yaml:"warningOnly,omitempty"`
+	Users              []string `json:"users,omitempty" yaml:"users,omitempty"`
+	Groups             []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	DomainUsersCanEdit bool     `json:"domainUsersCanEdit" yaml:"domainUsersCanEdit,omitempty"`
+	Unprotected        []string `json:"unprotected,omitempty" yaml:"unprotected,omitempty"`
+}
+
+// isWholeSheet reports whether 'gr' is unbounded in every direction
+func isWholeSheet(gr *sheets.GridRange) bool {
+	return gr.StartRowIndex == 0 && gr.EndRowIndex == 0 &&
+		gr.StartColumnIndex == 0 && gr.EndColumnIndex == 0
+}
+
+// newProtection resolves 'pr' against the sheets of 'ss'
+func newProtection(ss *sheets.Spreadsheet, pr *sheets.ProtectedRange) *Protection {
+	p := &Protection{
+		Id:          pr.ProtectedRangeId,
+		Description: pr.Description,
+		WarningOnly: pr.WarningOnly,
+	}
+	if pr.Range != nil {
+		p.SheetId = pr.Range.SheetId
+		p.SheetTitle = sheetTitle(ss, p.SheetId)
+		p.Range = A1FromGridRange(p.SheetTitle, pr.Range)
+		p.WholeSheet = isWholeSheet(pr.Range)
+	}
+	if pr.Editors != nil {
+		p.Users = pr.Editors.Users
+		p.Groups = pr.Editors.Groups
+		p.DomainUsersCanEdit = pr.Editors.DomainUsersCanEdit
+	}
+	for _, gr := range pr.UnprotectedRanges {
+		p.Unprotected = append(p.Unprotected, A1FromGridRange(sheetTitle(ss, gr.SheetId), gr))
+	}
+	return p
+}
+
+// toSheets converts the options in 'p' to a sheets.ProtectedRange covering
+// 'gr', resolving unprotected ranges against 'ss'
+func (p *Protection) toSheets(ss *sheets.Spreadsheet, gr *sheets.GridRange) (*sheets.ProtectedRange, error) {
+	if p.WarningOnly && (len(p.Users) > 0 || len(p.Groups) > 0 || p.DomainUsersCanEdit) {
+		return nil, errors.New("A warning-only protection cannot have editors")
+	}
+	pr := &sheets.ProtectedRange{
+		Range:       gr,
+		Description: p.Description,
+		WarningOnly: p.WarningOnly,
+	}
+	if !p.WarningOnly {
+		pr.Editors = &sheets.Editors{
+			Users:              p.Users,
+			Groups:             p.Groups,
+			DomainUsersCanEdit: p.DomainUsersCanEdit,
+		}
+	}
+	if len(p.Unprotected) > 0 && gr != nil && !isWholeSheet(gr) {
+		return nil, errors.New("Unprotected ranges are only allowed when protecting a whole sheet")
+	}
+	for _, a1 := range p.Unprotected {
+		ugr, err := gridRange(ss, a1)
+		if err != nil {
+			return nil, err
+		}
+		pr.UnprotectedRanges = append(pr.UnprotectedRanges, ugr)
+	}
+	return pr, nil
+}
+
+// Protections returns all protected ranges and protected sheets in the
+// spreadsheet doc identified by 'id'
+func (svc *Service) Protections(id string) ([]*Protection, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets(properties,protectedRanges)").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	var protections []*Protection
+	for _, sheet := range ss.Sheets {
+		for _, pr := range sheet.ProtectedRanges {
+			protections = append(protections, newProtection(ss, pr))
+		}
+	}
+	return protections, nil
+}
+
+// Protect protects 'a1Range' in the spreadsheet doc identified by 'id' using
+// the options in 'p'.
+// If 'a1Range' is just a sheet title, the whole sheet is protected.
+func (svc *Service) Protect(id, a1Range string, p *Protection) (*Protection, error) {
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	gr, err := gridRange(ss, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := p.toSheets(ss, gr)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{AddProtectedRange: &sheets.AddProtectedRangeRequest{ProtectedRange: pr}},
+		},
+	}).Context(svc.ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return newProtection(ss, resp.Replies[0].AddProtectedRange.ProtectedRange), nil
+}
+
+// UpdateProtection changes the options of the protection identified by
+// 'protectionId' in the spreadsheet doc identified by 'id' to those in 'p'.
+// Only the options named in 'fields' are changed, which may be any of
+// "description", "warningOnly", "editors" (Users, Groups and
+// DomainUsersCanEdit together) and "unprotectedRanges"; the others are kept.
+// If 'a1Range' is not empty the protection is also moved to cover it.
+func (svc *Service) UpdateProtection(id string, protectionId int64, a1Range string, p *Protection, fields []string) error {
+	if a1Range != "" {
+		fields = append(fields, "range")
+	}
+	if len(fields) == 0 {
+		return errors.New("Nothing to update")
+	}
+	ss, err := svc.sheet.Get(id).Fields("sheets.properties,namedRanges").Context(svc.ctx).Do()
+	if err != nil {
+		return err
+	}
+	var gr *sheets.GridRange
+	if a1Range != "" {
+		gr, err = gridRange(ss, a1Range)
+		if err != nil {
+			return err
+		}
+	}
+	pr, err := p.toSheets(ss, gr)
+	if err != nil {
+		return err
+	}
+	pr.ProtectedRangeId = protectionId
+	_, err = svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				UpdateProtectedRange: &sheets.UpdateProtectedRangeRequest{
+					ProtectedRange: pr,
+					Fields:         strings.Join(fields, ","),
+				},
+			},
+		},
+	}).Context(svc.ctx).Do()
+	return err
+}
+
+// Unprotect removes the protection identified by 'protectionId' from the
+// spreadsheet doc identified by 'id'
+func (svc *Service) Unprotect(id string, protectionId int64) error {
+	if protectionId == 0 {
+		return errors.New("protection id cannot be 0")
+	}
+	_, err := svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{
+			{
+				DeleteProtectedRange: &sheets.DeleteProtectedRangeRequest{
+					ProtectedRangeId: protectionId,
+				},
+			},
+		},
+	}).Context(svc.ctx).Do()
+	return err
+}
@@ -0,0 +1,826 @@
+package gsheets
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Schema is a declarative description of the structure of a spreadsheet doc:
+// its sheets, headers, formats, validations, protections, named ranges and
+// conditional formats (but not its data).
+// A schema exported from one document can be applied to others to make them
+// match it. Sheets which exist in a document but not in the schema (and the
+// protections, named ranges and conditional formats on them) are left alone;
+// everything else the schema describes is made to match exactly.
+// The yaml tags allow schemas to be kept in files.
+type Schema struct {
+	Sheets             []*SheetSchema       `yaml:"sheets"`
+	NamedRanges        []*NamedRange        `yaml:"namedRanges,omitempty"`
+	Protections        []*Protection        `yaml:"protections,omitempty"`
+	ConditionalFormats []*ConditionalFormat `yaml:"conditionalFormats,omitempty"`
+}
+
+// SheetSchema describes one sheet of a Schema.
+// Headers, if given, are the values of the first row. HeaderFormat is applied
+// to the whole first row and each of Columns to the rest of its column.
+type SheetSchema struct {
+	Title         string          `yaml:"title"`
+	FrozenRows    int64           `yaml:"frozenRows,omitempty"`
+	FrozenColumns int64           `yaml:"frozenColumns,omitempty"`
+	Hidden        bool            `yaml:"hidden,omitempty"`
+	TabColor      string          `yaml:"tabColor,omitempty"`
+	Headers       []string        `yaml:"headers,omitempty"`
+	HeaderFormat  *CellFormat     `yaml:"headerFormat,omitempty"`
+	Columns       []*ColumnSchema `yaml:"columns,omitempty"`
+}
+
+// ColumnSchema is the format and validation of the data cells (every row but
+// the first) of a column, identified by its letter ("C").
+// When exporting, these are read from the first data row.
+type ColumnSchema struct {
+	Column     string      `yaml:"column"`
+	Format     *CellFormat `yaml:"format,omitempty"`
+	Validation *Validation `yaml:"validation,omitempty"`
+}
+
+// ConditionalFormat applies Format to cells in Ranges for which Condition
+// holds. Condition and Values are as for Validation (NUMBER_GREATER,
+// TEXT_CONTAINS, CUSTOM_FORMULA, ...).
+// Only bold, italic, foreground and background colors can be used in a
+// conditional format. Gradient (color scale) rules are not supported and are
+// left as they are.
+type ConditionalFormat struct {
+	Ranges    []string    `yaml:"ranges"`
+	Condition string      `yaml:"condition"`
+	Values    []string    `yaml:"values,omitempty"`
+	Format    *CellFormat `yaml:"format"`
+}
+
+// SchemaChange is one change needed to make a spreadsheet doc match a Schema
+// along with the requests which make it.
+// Action is "add", "update" or "delete".
+type SchemaChange struct {
+	Action      string
+	Description string
+	Requests    []*sheets.Request
+}
+
+// String returns the change prefixed by +, ~ or - for its action
+func (c *SchemaChange) String() string {
+	prefix := map[string]string{"add": "+", "update": "~", "delete": "-"}[c.Action]
+	return prefix + " " + c.Description
+}
+
+// formatFromSheets converts a sheets.CellFormat back into a CellFormat. It
+// returns nil if none of the formatting supported by CellFormat is set.
+func formatFromSheets(cf *sheets.CellFormat) *CellFormat {
+	if cf == nil {
+		return nil
+	}
+	f := &CellFormat{
+		HorizontalAlignment: cf.HorizontalAlignment,
+		VerticalAlignment:   cf.VerticalAlignment,
+		WrapStrategy:        cf.WrapStrategy,
+		BackgroundColor:     colorHex(cf.BackgroundColor),
+	}
+	if cf.NumberFormat != nil {
+		f.NumberFormat = cf.NumberFormat.Pattern
+		f.NumberType = cf.NumberFormat.Type
+	}
+	if tf := cf.TextFormat; tf != nil {
+		if tf.Bold {
+			f.Bold = &tf.Bold
+		}
+		if tf.Italic {
+			f.Italic = &tf.Italic
+		}
+		f.ForegroundColor = colorHex(tf.ForegroundColor)
+	}
+	if b := cf.Borders; b != nil {
+		border := func(sb *sheets.Border) *Border {
+			if sb == nil || sb.Style == "" {
+				return nil
+			}
+			return &Border{Style: sb.Style, Color: colorHex(sb.Color)}
+		}
+		f.Top, f.Bottom, f.Left, f.Right = border(b.Top), border(b.Bottom), border(b.Left), border(b.Right)
+		if f.Top != nil && reflect.DeepEqual(f.Top, f.Bottom) &&
+			reflect.DeepEqual(f.Top, f.Left) && reflect.DeepEqual(f.Top, f.Right) {
+			f.Borders = f.Top
+			f.Top, f.Bottom, f.Left, f.Right = nil, nil, nil, nil
+		}
+	}
+	if *f == (CellFormat{}) {
+		return nil
+	}
+	return f
+}
+
+// normalFormat returns 'f' as it would be read back after applying it, so
+// that formats can be compared
+func normalFormat(f *CellFormat) (*CellFormat, error) {
+	if f == nil || *f == (CellFormat{}) {
+		return nil, nil
+	}
+	cf, _, err := f.toSheets()
+	if err != nil {
+		return nil, err
+	}
+	return formatFromSheets(cf), nil
+}
+
+// validationFromSheets converts a sheets.DataValidationRule back into a
+// Validation
+func validationFromSheets(rule *sheets.DataValidationRule) *Validation {
+	if rule == nil || rule.Condition == nil {
+		return nil
+	}
+	v := &Validation{
+		Condition:    rule.Condition.Type,
+		Strict:       rule.Strict,
+		InputMessage: rule.InputMessage,
+	}
+	for _, val := range rule.Condition.Values {
+		v.Values = append(v.Values, val.UserEnteredValue)
+	}
+	return v
+}
+
+// normalValidation returns 'v' as it would be read back after applying it
+func normalValidation(v *Validation) (*Validation, error) {
+	if v == nil {
+		return nil, nil
+	}
+	rule, err := v.toSheets()
+	if err != nil {
+		return nil, err
+	}
+	return validationFromSheets(rule), nil
+}
+
+// toSheets converts the ConditionalFormat to a sheets.ConditionalFormatRule,
+// resolving its ranges against 'ss'
+func (cf *ConditionalFormat) toSheets(ss *sheets.Spreadsheet) (*sheets.ConditionalFormatRule, error) {
+	rule := &sheets.ConditionalFormatRule{
+		BooleanRule: &sheets.BooleanRule{
+			Condition: &sheets.BooleanCondition{Type: strings.ToUpper(cf.Condition)},
+		},
+	}
+	for _, a1 := range cf.Ranges {
+		gr, err := gridRange(ss, a1)
+		if err != nil {
+			return nil, err
+		}
+		rule.Ranges = append(rule.Ranges, gr)
+	}
+	for _, val := range cf.Values {
+		rule.BooleanRule.Condition.Values = append(rule.BooleanRule.Condition.Values,
+			&sheets.ConditionValue{UserEnteredValue: val})
+	}
+	if cf.Format != nil {
+		format, _, err := cf.Format.toSheets()
+		if err != nil {
+			return nil, err
+		}
+		rule.BooleanRule.Format = format
+	}
+	return rule, nil
+}
+
+// newConditionalFormat resolves the boolean rule 'rule' against the sheets of
+// 'ss'. It returns nil for gradient rules.
+func newConditionalFormat(ss *sheets.Spreadsheet, rule *sheets.ConditionalFormatRule) *ConditionalFormat {
+	if rule.BooleanRule == nil || rule.BooleanRule.Condition == nil {
+		return nil
+	}
+	cf := &ConditionalFormat{
+		Condition: rule.BooleanRule.Condition.Type,
+		Format:    formatFromSheets(rule.BooleanRule.Format),
+	}
+	for _, gr := range rule.Ranges {
+		cf.Ranges = append(cf.Ranges, A1FromGridRange(sheetTitle(ss, gr.SheetId), gr))
+	}
+	for _, val := range rule.BooleanRule.Condition.Values {
+		cf.Values = append(cf.Values, val.UserEnteredValue)
+	}
+	return cf
+}
+
+// normalA1 returns 'a1Range' resolved against 'ss' and written back out in A1
+// notation, so that ranges can be compared
+func normalA1(ss *sheets.Spreadsheet, a1Range string) (string, error) {
+	gr, err := gridRange(ss, a1Range)
+	if err != nil {
+		return "", err
+	}
+	return A1FromGridRange(sheetTitle(ss, gr.SheetId), gr), nil
+}
+
+// normalColor returns a hex color in the form returned by colorHex
+func normalColor(hex string) (string, error) {
+	if hex == "" {
+		return "", nil
+	}
+	c, err := parseColor(hex)
+	if err != nil {
+		return "", err
+	}
+	return colorHex(c), nil
+}
+
+// schemaSpreadsheet fetches everything needed to export the schema of the
+// spreadsheet doc identified by 'id', including the grid data of the first
+// two rows of every sheet
+func (svc *Service) schemaSpreadsheet(id string) (*sheets.Spreadsheet, error) {
+	ss, err := svc.sheet.Get(id).
+		Fields("sheets(properties,protectedRanges,conditionalFormats),namedRanges").
+		Context(svc.ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	var ranges []string
+	for _, sheet := range ss.Sheets {
+		if sheet.Properties.SheetType == "GRID" {
+			ranges = append(ranges, quoteTitle(sheet.Properties.Title)+"!1:2")
+		}
+	}
+	if len(ranges) == 0 {
+		return ss, nil
+	}
+	data, err := svc.sheet.Get(id).
+		Ranges(ranges...).
+		IncludeGridData(true).
+		Fields("sheets(properties.sheetId,data.rowData.values(formattedValue,userEnteredFormat,dataValidation))").
+		Context(svc.ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range data.Sheets {
+		for _, sheet := range ss.Sheets {
+			if sheet.Properties.SheetId == d.Properties.SheetId {
+				sheet.Data = d.Data
+			}
+		}
+	}
+	return ss, nil
+}
+
+// exportSchema builds the Schema of 'ss' (as fetched by schemaSpreadsheet)
+func exportSchema(ss *sheets.Spreadsheet) *Schema {
+	schema := &Schema{}
+	for _, sheet := range ss.Sheets {
+		props := sheet.Properties
+		if props.SheetType != "GRID" {
+			continue
+		}
+		s := &SheetSchema{Title: props.Title, Hidden: props.Hidden}
+		if props.GridProperties != nil {
+			s.FrozenRows = props.GridProperties.FrozenRowCount
+			s.FrozenColumns = props.GridProperties.FrozenColumnCount
+		}
+		if props.TabColorStyle != nil {
+			s.TabColor = colorHex(props.TabColorStyle.RgbColor)
+		}
+
+		var rows []*sheets.RowData
+		if len(sheet.Data) > 0 {
+			rows = sheet.Data[0].RowData
+		}
+		if len(rows) > 0 {
+			for _, cell := range rows[0].Values {
+				s.Headers = append(s.Headers, cell.FormattedValue)
+			}
+			for len(s.Headers) > 0 && s.Headers[len(s.Headers)-1] == "" {
+				s.Headers = s.Headers[:len(s.Headers)-1]
+			}
+			if len(rows[0].Values) > 0 {
+				s.HeaderFormat = formatFromSheets(rows[0].Values[0].UserEnteredFormat)
+			}
+		}
+		if len(rows) > 1 {
+			for c, cell := range rows[1].Values {
+				col := &ColumnSchema{
+					Column:     columnLetters(int64(c)),
+					Format:     formatFromSheets(cell.UserEnteredFormat),
+					Validation: validationFromSheets(cell.DataValidation),
+				}
+				if col.Format != nil || col.Validation != nil {
+					s.Columns = append(s.Columns, col)
+				}
+			}
+		}
+		schema.Sheets = append(schema.Sheets, s)
+
+		for _, pr := range sheet.ProtectedRanges {
+			schema.Protections = append(schema.Protections, newProtection(ss, pr))
+		}
+		for _, rule := range sheet.ConditionalFormats {
+			if cf := newConditionalFormat(ss, rule); cf != nil {
+				schema.ConditionalFormats = append(schema.ConditionalFormats, cf)
+			}
+		}
+	}
+	for _, nr := range ss.NamedRanges {
+		schema.NamedRanges = append(schema.NamedRanges, newNamedRange(ss, nr))
+	}
+	return schema
+}
+
+// ExportSchema returns the Schema of the spreadsheet doc identified by 'id'.
+// Header formats are read from the first cell of each sheet and column
+// formats and validations from the second row.
+func (svc *Service) ExportSchema(id string) (*Schema, error) {
+	ss, err := svc.schemaSpreadsheet(id)
+	if err != nil {
+		return nil, err
+	}
+	return exportSchema(ss), nil
+}
+
+// PlanSchema compares the spreadsheet doc identified by 'id' with 'schema'
+// and returns the changes needed to make the document match it, without
+// making them (see ApplySchema).
+// An empty plan means the document already matches.
+func (svc *Service) PlanSchema(id string, schema *Schema) ([]*SchemaChange, error) {
+	ss, err := svc.schemaSpreadsheet(id)
+	if err != nil {
+		return nil, err
+	}
+	return planSchema(ss, schema)
+}
+
+// ApplySchema makes all of 'changes' (as returned by PlanSchema) to the
+// spreadsheet doc identified by 'id' in a single atomic update
+func (svc *Service) ApplySchema(id string, changes []*SchemaChange) error {
+	var reqs []*sheets.Request
+	for _, c := range changes {
+		reqs = append(reqs, c.Requests...)
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+	_, err := svc.batchUpdate(id, reqs...)
+	return err
+}
+
+// schemaPlanner accumulates the changes needed to make a spreadsheet match a
+// schema.
+// 'ss' includes the sheets which will be added by earlier changes so that
+// later changes can refer to them.
+type schemaPlanner struct {
+	ss      *sheets.Spreadsheet
+	current *Schema
+	changes []*SchemaChange
+	// managed holds the ids of the sheets described by the schema
+	managed map[int64]bool
+}
+
+func (p *schemaPlanner) add(action string, req *sheets.Request, format string, args ...interface{}) {
+	p.changes = append(p.changes, &SchemaChange{
+		Action:      action,
+		Description: fmt.Sprintf(format, args...),
+		Requests:    []*sheets.Request{req},
+	})
+}
+
+// newSheetId returns the lowest sheet id not already used in the plan
+func (p *schemaPlanner) newSheetId() int64 {
+	used := make(map[int64]bool)
+	for _, sheet := range p.ss.Sheets {
+		used[sheet.Properties.SheetId] = true
+	}
+	var id int64 = 1
+	for used[id] {
+		id++
+	}
+	return id
+}
+
+// planSchema computes the changes needed to make 'ss' (as fetched by
+// schemaSpreadsheet) match 'schema'
+func planSchema(ss *sheets.Spreadsheet, schema *Schema) ([]*SchemaChange, error) {
+	p := &schemaPlanner{
+		ss: &sheets.Spreadsheet{
+			Sheets:      append([]*sheets.Sheet{}, ss.Sheets...),
+			NamedRanges: ss.NamedRanges,
+		},
+		current: exportSchema(ss),
+		managed: make(map[int64]bool),
+	}
+	for _, s := range schema.Sheets {
+		if err := p.planSheet(s); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.planConditionalFormats(ss, schema.ConditionalFormats); err != nil {
+		return nil, err
+	}
+	if err := p.planProtections(schema.Protections); err != nil {
+		return nil, err
+	}
+	if err := p.planNamedRanges(schema.NamedRanges); err != nil {
+		return nil, err
+	}
+	return p.changes, nil
+}
+
+// planSheet plans the changes to a single sheet, adding it if necessary
+func (p *schemaPlanner) planSheet(s *SheetSchema) error {
+	if s.Title == "" {
+		return errors.New("Sheet in schema has no title")
+	}
+	tabColor, err := normalColor(s.TabColor)
+	if err != nil {
+		return err
+	}
+	quoted := quoteTitle(s.Title)
+
+	var cur *SheetSchema
+	for _, c := range p.current.Sheets {
+		if c.Title == s.Title {
+			cur = c
+		}
+	}
+	var sheetId int64
+	if cur == nil {
+		sheetId = p.newSheetId()
+		props := &sheets.SheetProperties{
+			SheetId: sheetId,
+			Title:   s.Title,
+			Hidden:  s.Hidden,
+			GridProperties: &sheets.GridProperties{
+				FrozenRowCount:    s.FrozenRows,
+				FrozenColumnCount: s.FrozenColumns,
+			},
+		}
+		if tabColor != "" {
+			color, _ := parseColor(tabColor)
+			props.TabColorStyle = &sheets.ColorStyle{RgbColor: color}
+		}
+		p.add("add", &sheets.Request{AddSheet: &sheets.AddSheetRequest{Properties: props}},
+			"add sheet %s", quoted)
+		p.ss.Sheets = append(p.ss.Sheets, &sheets.Sheet{
+			Properties: &sheets.SheetProperties{SheetId: sheetId, Title: s.Title, SheetType: "GRID"},
+		})
+		cur = &SheetSchema{Title: s.Title, FrozenRows: s.FrozenRows,
+			FrozenColumns: s.FrozenColumns, Hidden: s.Hidden, TabColor: tabColor}
+	} else {
+		for _, sheet := range p.ss.Sheets {
+			if sheet.Properties.Title == s.Title {
+				sheetId = sheet.Properties.SheetId
+			}
+		}
+	}
+	p.managed[sheetId] = true
+
+	props := &sheets.SheetProperties{SheetId: sheetId, GridProperties: &sheets.GridProperties{}}
+	var fields, diffs []string
+	if cur.FrozenRows != s.FrozenRows {
+		props.GridProperties.FrozenRowCount = s.FrozenRows
+		props.GridProperties.ForceSendFields = append(props.GridProperties.ForceSendFields, "FrozenRowCount")
+		fields = append(fields, "gridProperties.frozenRowCount")
+		diffs = append(diffs, fmt.Sprintf("frozenRows %d => %d", cur.FrozenRows, s.FrozenRows))
+	}
+	if cur.FrozenColumns != s.FrozenColumns {
+		props.GridProperties.FrozenColumnCount = s.FrozenColumns
+		props.GridProperties.ForceSendFields = append(props.GridProperties.ForceSendFields, "FrozenColumnCount")
+		fields = append(fields, "gridProperties.frozenColumnCount")
+		diffs = append(diffs, fmt.Sprintf("frozenColumns %d => %d", cur.FrozenColumns, s.FrozenColumns))
+	}
+	if cur.Hidden != s.Hidden {
+		props.Hidden = s.Hidden
+		props.ForceSendFields = append(props.ForceSendFields, "Hidden")
+		fields = append(fields, "hidden")
+		diffs = append(diffs, fmt.Sprintf("hidden %t => %t", cur.Hidden, s.Hidden))
+	}
+	if cur.TabColor != tabColor {
+		if tabColor != "" {
+			color, _ := parseColor(tabColor)
+			props.TabColorStyle = &sheets.ColorStyle{RgbColor: color}
+		}
+		fields = append(fields, "tabColorStyle")
+		diffs = append(diffs, fmt.Sprintf("tabColor %q => %q", cur.TabColor, tabColor))
+	}
+	if len(fields) > 0 {
+		p.add("update", &sheets.Request{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: props,
+				Fields:     strings.Join(fields, ","),
+			},
+		}, "update sheet %s: %s", quoted, strings.Join(diffs, ", "))
+	}
+
+	if s.Headers != nil && !reflect.DeepEqual(cur.Headers, s.Headers) {
+		var cells []*sheets.CellData
+		for i := 0; i < len(s.Headers) || i < len(cur.Headers); i++ {
+			cell := &sheets.CellData{}
+			if i < len(s.Headers) {
+				cell.UserEnteredValue = &sheets.ExtendedValue{StringValue: &s.Headers[i]}
+			}
+			cells = append(cells, cell)
+		}
+		p.add("update", &sheets.Request{
+			UpdateCells: &sheets.UpdateCellsRequest{
+				Start:  &sheets.GridCoordinate{SheetId: sheetId},
+				Rows:   []*sheets.RowData{{Values: cells}},
+				Fields: "userEnteredValue",
+			},
+		}, "update headers of %s: %q => %q", quoted, cur.Headers, s.Headers)
+	}
+
+	headerFormat, err := normalFormat(s.HeaderFormat)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(cur.HeaderFormat, headerFormat) {
+		gr := &sheets.GridRange{SheetId: sheetId, StartRowIndex: 0, EndRowIndex: 1}
+		p.add(changeAction(cur.HeaderFormat, headerFormat), replaceFormatRequest(gr, headerFormat),
+			"set header format of %s", quoted)
+	}
+
+	return p.planColumns(sheetId, quoted, cur.Columns, s.Columns)
+}
+
+// planColumns plans the changes to the column formats and validations of a
+// sheet
+func (p *schemaPlanner) planColumns(sheetId int64, quoted string, current, want []*ColumnSchema) error {
+	type column struct {
+		cur, want *ColumnSchema
+	}
+	columns := make(map[int64]*column)
+	for _, c := range current {
+		columns[columnIndex(c.Column)] = &column{cur: c, want: &ColumnSchema{}}
+	}
+	for _, w := range want {
+		if !isA1Cells(w.Column) || strings.ContainsAny(w.Column, "0123456789:") {
+			return fmt.Errorf("Invalid column in schema for %s: %s", quoted, w.Column)
+		}
+		format, err := normalFormat(w.Format)
+		if err != nil {
+			return err
+		}
+		validation, err := normalValidation(w.Validation)
+		if err != nil {
+			return err
+		}
+		c := columns[columnIndex(w.Column)]
+		if c == nil {
+			c = &column{cur: &ColumnSchema{}}
+			columns[columnIndex(w.Column)] = c
+		}
+		c.want = &ColumnSchema{Format: format, Validation: validation}
+	}
+
+	var indexes []int64
+	for i := range columns {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(a, b int) bool { return indexes[a] < indexes[b] })
+	for _, i := range indexes {
+		c := columns[i]
+		gr := &sheets.GridRange{SheetId: sheetId, StartRowIndex: 1, StartColumnIndex: i, EndColumnIndex: i + 1}
+		a1 := A1FromGridRange(quoted, gr)
+		if !reflect.DeepEqual(c.cur.Format, c.want.Format) {
+			p.add(changeAction(c.cur.Format, c.want.Format), replaceFormatRequest(gr, c.want.Format),
+				"set format of %s", a1)
+		}
+		if !reflect.DeepEqual(c.cur.Validation, c.want.Validation) {
+			req, err := validationRequest(gr, c.want.Validation)
+			if err != nil {
+				return err
+			}
+			p.add(changeAction(c.cur.Validation, c.want.Validation), req,
+				"set validation of %s", a1)
+		}
+	}
+	return nil
+}
+
+// changeAction returns whether changing 'from' to 'to' adds, updates or
+// deletes something
+func changeAction(from, to interface{}) string {
+	switch {
+	case reflect.ValueOf(from).IsNil():
+		return "add"
+	case reflect.ValueOf(to).IsNil():
+		return "delete"
+	}
+	return "update"
+}
+
+// replaceFormatRequest builds a repeatCell request which replaces all
+// formatting of the cells in 'gr' with 'format' (or clears it if 'format' is
+// nil)
+func replaceFormatRequest(gr *sheets.GridRange, format *CellFormat) *sheets.Request {
+	cell := &sheets.CellData{}
+	if format != nil {
+		// normalized formats always convert
+		cell.UserEnteredFormat, _, _ = format.toSheets()
+	}
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range:  gr,
+			Cell:   cell,
+			Fields: "userEnteredFormat",
+		},
+	}
+}
+
+// planConditionalFormats plans the changes to conditional formats. Each sheet
+// in the schema whose rules differ from it has its rules replaced.
+func (p *schemaPlanner) planConditionalFormats(ss *sheets.Spreadsheet, want []*ConditionalFormat) error {
+	wanted := make(map[int64][]*sheets.ConditionalFormatRule)
+	for _, cf := range want {
+		if len(cf.Ranges) == 0 {
+			return fmt.Errorf("Conditional format %s has no ranges", cf.Condition)
+		}
+		rule, err := cf.toSheets(p.ss)
+		if err != nil {
+			return err
+		}
+		sheetId := rule.Ranges[0].SheetId
+		if !p.managed[sheetId] {
+			return fmt.Errorf("Conditional format on %s is not on a sheet in the schema", cf.Ranges[0])
+		}
+		wanted[sheetId] = append(wanted[sheetId], rule)
+	}
+
+	for _, sheet := range p.ss.Sheets {
+		sheetId := sheet.Properties.SheetId
+		if !p.managed[sheetId] {
+			continue
+		}
+
+		// rules are compared as they would be read back
+		var cur, next []*ConditionalFormat
+		var curIndexes []int64
+		for i, rule := range sheet.ConditionalFormats {
+			if cf := newConditionalFormat(ss, rule); cf != nil {
+				cur = append(cur, cf)
+				curIndexes = append(curIndexes, int64(i))
+			}
+		}
+		for _, rule := range wanted[sheetId] {
+			next = append(next, newConditionalFormat(p.ss, rule))
+		}
+		if reflect.DeepEqual(cur, next) {
+			continue
+		}
+
+		for i := len(curIndexes) - 1; i >= 0; i-- {
+			p.add("delete", &sheets.Request{
+				DeleteConditionalFormatRule: &sheets.DeleteConditionalFormatRuleRequest{
+					SheetId:         sheetId,
+					Index:           curIndexes[i],
+					ForceSendFields: []string{"SheetId", "Index"},
+				},
+			}, "delete conditional format %s on %s", cur[i].Condition, strings.Join(cur[i].Ranges, ","))
+		}
+		for i, rule := range wanted[sheetId] {
+			p.add("add", &sheets.Request{
+				AddConditionalFormatRule: &sheets.AddConditionalFormatRuleRequest{
+					Rule:            rule,
+					Index:           int64(i),
+					ForceSendFields: []string{"Index"},
+				},
+			}, "add conditional format %s on %s", next[i].Condition, strings.Join(next[i].Ranges, ","))
+		}
+	}
+	return nil
+}
+
+// protectionMatches reports whether the existing protection 'cur' already has
+// the options of 'want'.
+// The user who creates a protection is always added as an editor, so 'cur'
+// matches as long as it includes all of the users in 'want'.
+func protectionMatches(cur, want *Protection) bool {
+	sorted := func(s []string) []string {
+		s = append([]string{}, s...)
+		sort.Strings(s)
+		return s
+	}
+	users := make(map[string]bool)
+	for _, u := range cur.Users {
+		users[strings.ToLower(u)] = true
+	}
+	for _, u := range want.Users {
+		if !users[strings.ToLower(u)] {
+			return false
+		}
+	}
+	return cur.WarningOnly == want.WarningOnly &&
+		cur.DomainUsersCanEdit == want.DomainUsersCanEdit &&
+		reflect.DeepEqual(sorted(cur.Groups), sorted(want.Groups)) &&
+		reflect.DeepEqual(sorted(cur.Unprotected), sorted(want.Unprotected))
+}
+
+// planProtections plans the changes to protected ranges and sheets.
+// Protections are matched by range and description. Protections on sheets
+// which are not in the schema are left alone.
+func (p *schemaPlanner) planProtections(want []*Protection) error {
+	key := func(pr *Protection) string {
+		return pr.Range + "\x00" + pr.Description
+	}
+	current := make(map[string]*Protection)
+	for _, pr := range p.current.Protections {
+		current[key(pr)] = pr
+	}
+
+	for _, w := range want {
+		gr, err := gridRange(p.ss, w.Range)
+		if err != nil {
+			return err
+		}
+		norm := *w
+		norm.Range = A1FromGridRange(sheetTitle(p.ss, gr.SheetId), gr)
+		norm.Unprotected = nil
+		for _, a1 := range w.Unprotected {
+			u, err := normalA1(p.ss, a1)
+			if err != nil {
+				return err
+			}
+			norm.Unprotected = append(norm.Unprotected, u)
+		}
+		pr, err := norm.toSheets(p.ss, gr)
+		if err != nil {
+			return err
+		}
+
+		cur := current[key(&norm)]
+		delete(current, key(&norm))
+		switch {
+		case cur == nil:
+			p.add("add", &sheets.Request{
+				AddProtectedRange: &sheets.AddProtectedRangeRequest{ProtectedRange: pr},
+			}, "add protection of %s", norm.Range)
+		case !protectionMatches(cur, &norm):
+			pr.ProtectedRangeId = cur.Id
+			p.add("update", &sheets.Request{
+				UpdateProtectedRange: &sheets.UpdateProtectedRangeRequest{
+					ProtectedRange: pr,
+					Fields:         "warningOnly,editors,unprotectedRanges",
+				},
+			}, "update protection of %s", norm.Range)
+		}
+	}
+
+	for _, cur := range p.current.Protections {
+		if current[key(cur)] == cur && p.managed[cur.SheetId] {
+			p.add("delete", &sheets.Request{
+				DeleteProtectedRange: &sheets.DeleteProtectedRangeRequest{ProtectedRangeId: cur.Id},
+			}, "delete protection of %s", cur.Range)
+		}
+	}
+	return nil
+}
+
+// planNamedRanges plans the changes to named ranges, which are matched by
+// name. Named ranges on sheets which are not in the schema are left alone.
+func (p *schemaPlanner) planNamedRanges(want []*NamedRange) error {
+	current := make(map[string]*NamedRange)
+	for _, nr := range p.current.NamedRanges {
+		current[nr.Name] = nr
+	}
+
+	for _, w := range want {
+		gr, err := gridRange(p.ss, w.Range)
+		if err != nil {
+			return err
+		}
+		a1 := A1FromGridRange(sheetTitle(p.ss, gr.SheetId), gr)
+
+		cur := current[w.Name]
+		delete(current, w.Name)
+		switch {
+		case cur == nil:
+			p.add("add", &sheets.Request{
+				AddNamedRange: &sheets.AddNamedRangeRequest{
+					NamedRange: &sheets.NamedRange{Name: w.Name, Range: gr},
+				},
+			}, "add named range %s: %s", w.Name, a1)
+		case cur.Range != a1:
+			p.add("update", &sheets.Request{
+				UpdateNamedRange: &sheets.UpdateNamedRangeRequest{
+					NamedRange: &sheets.NamedRange{NamedRangeId: cur.Id, Name: w.Name, Range: gr},
+					Fields:     "range",
+				},
+			}, "update named range %s: %s => %s", w.Name, cur.Range, a1)
+		}
+	}
+
+	for _, cur := range p.current.NamedRanges {
+		if current[cur.Name] == cur && p.managed[cur.SheetId] {
+			p.add("delete", &sheets.Request{
+				DeleteNamedRange: &sheets.DeleteNamedRangeRequest{NamedRangeId: cur.Id},
+			}, "delete named range %s", cur.Name)
+		}
+	}
+	return nil
+}
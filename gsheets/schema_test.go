@@ -0,0 +1,96 @@
+package gsheets
+
+import (
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// schemaTestSpreadsheet returns a spreadsheet as fetched by schemaSpreadsheet
+// with one sheet, "Data", which has two headers and a bold second column
+func schemaTestSpreadsheet() *sheets.Spreadsheet {
+	a, b := "Date", "Total"
+	return &sheets.Spreadsheet{
+		Sheets: []*sheets.Sheet{
+			{
+				Properties: &sheets.SheetProperties{
+					SheetId:        0,
+					Title:          "Data",
+					SheetType:      "GRID",
+					GridProperties: &sheets.GridProperties{},
+				},
+				Data: []*sheets.GridData{{
+					RowData: []*sheets.RowData{
+						{Values: []*sheets.CellData{
+							{FormattedValue: a, UserEnteredValue: &sheets.ExtendedValue{StringValue: &a}},
+							{FormattedValue: b, UserEnteredValue: &sheets.ExtendedValue{StringValue: &b}},
+						}},
+						{Values: []*sheets.CellData{
+							{},
+							{UserEnteredFormat: &sheets.CellFormat{TextFormat: &sheets.TextFormat{Bold: true}}},
+						}},
+					},
+				}},
+			},
+		},
+		NamedRanges: []*sheets.NamedRange{
+			{NamedRangeId: "nr1", Name: "old", Range: &sheets.GridRange{EndRowIndex: 1}},
+		},
+	}
+}
+
+func TestPlanSchemaExported(t *testing.T) {
+	ss := schemaTestSpreadsheet()
+	changes, err := planSchema(ss, exportSchema(ss))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range changes {
+		t.Errorf("unexpected change: %s", c)
+	}
+}
+
+func TestPlanSchema(t *testing.T) {
+	bold := true
+	schema := &Schema{
+		Sheets: []*SheetSchema{
+			{
+				Title:      "Data",
+				FrozenRows: 1,
+				Headers:    []string{"Date", "Total", "Notes"},
+				Columns:    []*ColumnSchema{{Column: "B", Format: &CellFormat{Bold: &bold}}},
+			},
+			{Title: "Summary", HeaderFormat: &CellFormat{Bold: &bold}},
+		},
+		NamedRanges: []*NamedRange{{Name: "totals", Range: "Summary!B2:B"}},
+	}
+	changes, err := planSchema(schemaTestSpreadsheet(), schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"~ update sheet 'Data': frozenRows 0 => 1",
+		`~ update headers of 'Data': ["Date" "Total"] => ["Date" "Total" "Notes"]`,
+		"+ add sheet 'Summary'",
+		"+ set header format of 'Summary'",
+		"+ add named range totals: 'Summary'!B2:B",
+		"- delete named range old",
+	}
+	if len(changes) != len(want) {
+		for _, c := range changes {
+			t.Log(c)
+		}
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, c := range changes {
+		if c.String() != want[i] {
+			t.Errorf("change %d: got %q, want %q", i, c, want[i])
+		}
+	}
+	if id := changes[2].Requests[0].AddSheet.Properties.SheetId; id != 1 {
+		t.Errorf("new sheet id: got %d, want 1", id)
+	}
+	if id := changes[4].Requests[0].AddNamedRange.NamedRange.Range.SheetId; id != 1 {
+		t.Errorf("named range sheet id: got %d, want 1", id)
+	}
+}
@@ -10,8 +10,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
-	"errors"
-	"fmt"
 	"io"
 
 	"google.golang.org/api/sheets/v4"
@@ -32,12 +30,19 @@ type valueService interface {
 	BatchClear(string, *sheets.BatchClearValuesRequest) *sheets.SpreadsheetsValuesBatchClearCall
 }
 
+// Define an interface so we can mock the SpreadsheetsDeveloperMetadataService
+// if we need to
+type metadataService interface {
+	Search(string, *sheets.SearchDeveloperMetadataRequest) *sheets.SpreadsheetsDeveloperMetadataSearchCall
+}
+
 // Service is a wrapper around both SpreadsheetsService and SpreadsheetsValuesService
 type Service struct {
-	Sep    rune // record separator when [un]serializing csv
-	ctx    context.Context
-	sheet  ssService
-	values valueService
+	Sep      rune // record separator when [un]serializing csv
+	ctx      context.Context
+	sheet    ssService
+	values   valueService
+	metadata metadataService
 }
 
 // NewServiceWithCtx creates and wraps a new Service with the provided context
@@ -47,10 +52,11 @@ func NewServiceWithCtx(ctx context.Context) (*Service, error) {
 		return nil, err
 	}
 	return &Service{
-		Sep:    ',',
-		ctx:    ctx,
-		sheet:  ssvc.Spreadsheets,
-		values: ssvc.Spreadsheets.Values,
+		Sep:      ',',
+		ctx:      ctx,
+		sheet:    ssvc.Spreadsheets,
+		values:   ssvc.Spreadsheets.Values,
+		metadata: ssvc.Spreadsheets.DeveloperMetadata,
 	}, nil
 }
 
@@ -59,23 +65,19 @@ func (svc *Service) SpreadsheetsService() *sheets.SpreadsheetsService {
 	return svc.sheet.(*sheets.SpreadsheetsService)
 }
 
+// batchUpdate sends 'reqs' to the spreadsheet doc identified by 'id' in a
+// single BatchUpdate call
+func (svc *Service) batchUpdate(id string, reqs ...*sheets.Request) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	return svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: reqs,
+	}).Context(svc.ctx).Do()
+}
+
 // NewSheet creates a new sheet on spreadsheet identified by 'id'
 func (svc *Service) NewSheet(id, title string) error {
-	if id == "" {
-		return errors.New("id cannot be empty")
-	}
-	_, err := svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			&sheets.Request{
-				AddSheet: &sheets.AddSheetRequest{
-					Properties: &sheets.SheetProperties{
-						Title: title,
-					},
-				},
-			},
-		},
-	}).Context(svc.ctx).Do()
-	return err
+	b := svc.NewBatch(id)
+	b.AddSheet(title)
+	return b.Do()
 }
 
 // SheetFromTitle returns the sheetID for the sheet with 'title' in the
@@ -116,31 +118,58 @@ func (svc *Service) TitleFromSheetId(id string, sheetId int64) (*string, error)
 	return sheetTitle, nil
 }
 
-// DeleteSheet deletes the sheet with 'title' from spreadsheet doc identified
-// by 'id'
-func (svc *Service) DeleteSheet(id, title string) error {
-	if id == "" {
-		return errors.New("id cannot be empty")
-	}
-	// find sheet matching title
-	sheetId, err := svc.SheetFromTitle(id, title)
+// SheetInfo describes a sheet (tab) of a spreadsheet doc.
+// TabColor is a hex string ("#RRGGBB"), or the name of a theme color (eg
+// "ACCENT1") if the tab is colored with one.
+type SheetInfo struct {
+	Id            int64  `json:"id"`
+	Title         string `json:"title"`
+	Index         int64  `json:"index"`
+	Rows          int64  `json:"rows"`
+	Columns       int64  `json:"columns"`
+	Hidden        bool   `json:"hidden"`
+	TabColor      string `json:"tabColor,omitempty"`
+	FrozenRows    int64  `json:"frozenRows"`
+	FrozenColumns int64  `json:"frozenColumns"`
+}
+
+// ListSheets returns information about each sheet in the spreadsheet doc
+// identified by 'id', in tab order.
+func (svc *Service) ListSheets(id string) ([]*SheetInfo, error) {
+	ss, err := svc.sheet.Get(id).
+		Fields("sheets.properties(sheetId,title,index,hidden,tabColorStyle,gridProperties(rowCount,columnCount,frozenRowCount,frozenColumnCount))").
+		Context(svc.ctx).
+		Do()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if sheetId == nil {
-		return errors.New("No sheet found with title: " + title)
+	infos := make([]*SheetInfo, len(ss.Sheets))
+	for i, sheet := range ss.Sheets {
+		props := sheet.Properties
+		info := &SheetInfo{
+			Id:     props.SheetId,
+			Title:  props.Title,
+			Index:  props.Index,
+			Hidden: props.Hidden,
+		}
+		if grid := props.GridProperties; grid != nil {
+			info.Rows = grid.RowCount
+			info.Columns = grid.ColumnCount
+			info.FrozenRows = grid.FrozenRowCount
+			info.FrozenColumns = grid.FrozenColumnCount
+		}
+		if props.TabColorStyle != nil {
+			info.TabColor = colorStyleName(props.TabColorStyle)
+		}
+		infos[i] = info
 	}
+	return infos, nil
+}
 
-	_, err = svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{
-			&sheets.Request{
-				DeleteSheet: &sheets.DeleteSheetRequest{
-					SheetId: *sheetId,
-				},
-			},
-		},
-	}).Context(svc.ctx).Do()
-	return err
+// DeleteSheet deletes the sheet with 'title' from spreadsheet doc identified
+// by 'id'
+func (svc *Service) DeleteSheet(id, title string) error {
+	return svc.NewBatch(id).DeleteSheet(title).Do()
 }
 
 // GetRangeRaw gets unformatted values in 'a1Range' from the spreadsheet doc
@@ -344,34 +373,5 @@ func (svc *Service) Clear(id string, a1Ranges ...string) error {
 // If asc is true, sort ascending; otherwise sort descending
 // Column is the column index rather than A1 notation (0=A, 1=B, ...)
 func (svc *Service) Sort(id, name string, asc bool, column int64) error {
-	sheetId, err := svc.SheetFromTitle(id, name)
-	if err != nil {
-		return err
-	}
-
-	if sheetId == nil {
-		return fmt.Errorf("No sheet titled %s found", name)
-	}
-
-	order := "DESCENDING"
-	if asc {
-		order = "ASCENDING"
-	}
-
-	sortSpec := sheets.Request{
-		SortRange: &sheets.SortRangeRequest{
-			Range: &sheets.GridRange{
-				SheetId: *sheetId,
-			},
-			SortSpecs: []*sheets.SortSpec{
-				&sheets.SortSpec{
-					DimensionIndex: column,
-					SortOrder:      order,
-				}},
-		},
-	}
-	_, err = svc.sheet.BatchUpdate(id, &sheets.BatchUpdateSpreadsheetRequest{
-		Requests: []*sheets.Request{&sortSpec},
-	}).Context(svc.ctx).Do()
-	return err
+	return svc.NewBatch(id).Sort(name, asc, column).Do()
 }
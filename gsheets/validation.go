@@ -0,0 +1,109 @@
+package gsheets
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// Validation describes a data validation rule for a range of cells.
+// Condition is a sheets ConditionType (ONE_OF_LIST, ONE_OF_RANGE,
+// NUMBER_BETWEEN, NUMBER_GREATER, DATE_BEFORE, DATE_IS_VALID, BOOLEAN,
+// CUSTOM_FORMULA, ...) and Values are the values the condition requires.
+// If Strict is false, invalid input is accepted but flagged with a warning.
+// InputMessage is shown to the user when they select a validated cell.
+// see: https://developers.google.com/sheets/api/reference/rest/v4/spreadsheets/other#ConditionType
+type Validation struct {
+	Condition    string   `yaml:"condition"`
+	Values       []string `yaml:"values,omitempty"`
+	Strict       bool     `yaml:"strict,omitempty"`
+	InputMessage string   `yaml:"inputMessage,omitempty"`
+}
+
+// OneOf returns a Validation which only allows the given values (shown as a
+// dropdown)
+func OneOf(values ...string) *Validation {
+	return &Validation{Condition: "ONE_OF_LIST", Values: values, Strict: true}
+}
+
+// OneOfRange returns a Validation which only allows values found in
+// 'a1Range' (shown as a dropdown)
+func OneOfRange(a1Range string) *Validation {
+	return &Validation{Condition: "ONE_OF_RANGE", Values: []string{a1Range}, Strict: true}
+}
+
+// Checkbox returns a Validation which renders cells as checkboxes
+func Checkbox() *Validation {
+	return &Validation{Condition: "BOOLEAN", Strict: true}
+}
+
+// CustomFormula returns a Validation which only allows values for which
+// 'formula' evaluates to true
+func CustomFormula(formula string) *Validation {
+	return &Validation{Condition: "CUSTOM_FORMULA", Values: []string{formula}, Strict: true}
+}
+
+// toSheets converts the Validation to a sheets.DataValidationRule
+func (v *Validation) toSheets() (*sheets.DataValidationRule, error) {
+	condition := strings.ToUpper(v.Condition)
+	if condition == "" {
+		return nil, errors.New("Validation condition cannot be empty")
+	}
+
+	var values []*sheets.ConditionValue
+	for _, val := range v.Values {
+		switch condition {
+		case "ONE_OF_RANGE", "CUSTOM_FORMULA":
+			// these conditions take a formula
+			if !strings.HasPrefix(val, "=") {
+				val = "=" + val
+			}
+		}
+		values = append(values, &sheets.ConditionValue{UserEnteredValue: val})
+	}
+
+	showDropdown := condition == "ONE_OF_LIST" || condition == "ONE_OF_RANGE"
+	return &sheets.DataValidationRule{
+		Condition: &sheets.BooleanCondition{
+			Type:   condition,
+			Values: values,
+		},
+		Strict:       v.Strict,
+		InputMessage: v.InputMessage,
+		ShowCustomUi: showDropdown,
+	}, nil
+}
+
+// validationRequest builds a request which sets 'v' on every cell in 'gr'. If
+// 'v' is nil the request clears any validation from 'gr'.
+func validationRequest(gr *sheets.GridRange, v *Validation) (*sheets.Request, error) {
+	req := &sheets.SetDataValidationRequest{Range: gr}
+	if v != nil {
+		rule, err := v.toSheets()
+		if err != nil {
+			return nil, err
+		}
+		req.Rule = rule
+	}
+	return &sheets.Request{SetDataValidation: req}, nil
+}
+
+// SetValidation sets the data validation rule 'v' on every cell in 'a1Range'
+// of the spreadsheet doc identified by 'id', replacing any existing rule.
+func (svc *Service) SetValidation(id, a1Range string, v *Validation) error {
+	if v == nil {
+		return errors.New("validation cannot be nil (use ClearValidation)")
+	}
+	return svc.setValidation(id, a1Range, v)
+}
+
+// ClearValidation removes any data validation from 'a1Range' of the
+// spreadsheet doc identified by 'id'.
+func (svc *Service) ClearValidation(id, a1Range string) error {
+	return svc.setValidation(id, a1Range, nil)
+}
+
+func (svc *Service) setValidation(id, a1Range string, v *Validation) error {
+	return svc.NewBatch(id).SetValidation(a1Range, v).Do()
+}